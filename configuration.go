@@ -0,0 +1,29 @@
+package goria
+
+import "errors"
+
+var ErrInvalidSize = errors.New("The Goria Cache need a positive value as size")
+
+// CacheFactory builds a cache named name out of config, it is provided by every eviction policy package.
+type CacheFactory[K comparable, V any] func(name string, config Configuration[K, V]) (Cache[K, V], error)
+
+// Configuration describes a cache to be created through a CacheManager.
+// When ReadThrough is set, Get and GetAll populate their misses through the CacheLoader,
+// when WriteThrough is set, the mutations are written through the CacheWriter.
+// When MaxWeight is positive, entries are also evicted until the sum of their weights fits MaxWeight,
+// every entry weighing 1 unless a Weigher is given, while Size keeps capping the number of entries.
+// When a MetricsRecorder is given, it is told about the hits, misses, evictions and loads of the cache.
+type Configuration[K comparable, V any] struct {
+	Size             int
+	EvictionCallback EvictionCallback[K, V]
+	StatsEnabled     bool
+	ExpiryPolicy     ExpiryPolicy
+	CacheLoader      CacheLoader[K, V]
+	ReadThrough      bool
+	CacheWriter      CacheWriter[K, V]
+	WriteThrough     bool
+	Weigher          Weigher[K, V]
+	MaxWeight        int64
+	MetricsRecorder  MetricsRecorder
+	Factory          CacheFactory[K, V]
+}
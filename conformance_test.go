@@ -0,0 +1,181 @@
+package goria_test
+
+import (
+	"container/list"
+	"reflect"
+	"testing"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/goria2q"
+	"github.com/oscerd/goria/goriaarc"
+	"github.com/oscerd/goria/goriaclock"
+	"github.com/oscerd/goria/goriacustom"
+	"github.com/oscerd/goria/goriafifo"
+	"github.com/oscerd/goria/gorialfu"
+	"github.com/oscerd/goria/gorialirs"
+	"github.com/oscerd/goria/gorialru"
+	"github.com/oscerd/goria/goriamru"
+	"github.com/oscerd/goria/goriarandom"
+	"github.com/oscerd/goria/goriasampled"
+	"github.com/oscerd/goria/goriasieve"
+	"github.com/oscerd/goria/goriaslru"
+	"github.com/oscerd/goria/goriatinylfu"
+)
+
+// queuePolicy evicts the oldest key, so that goriacustom is checked like the other packages.
+type queuePolicy[K comparable] struct {
+	queue    *list.List
+	elements map[K]*list.Element
+}
+
+func newQueuePolicy(size int) goria.EvictionPolicy[int] {
+	return &queuePolicy[int]{list.New(), make(map[int]*list.Element)}
+}
+
+func (p *queuePolicy[K]) OnAccess(key K) {}
+
+func (p *queuePolicy[K]) OnInsert(key K) {
+	p.elements[key] = p.queue.PushFront(key)
+}
+
+func (p *queuePolicy[K]) OnRemove(key K) {
+	p.queue.Remove(p.elements[key])
+	delete(p.elements, key)
+}
+
+func (p *queuePolicy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	for element := p.queue.Back(); element != nil; element = element.Prev() {
+		if key := element.Value.(K); evictable(key) {
+			return key, true
+		}
+	}
+	return
+}
+
+// TestConformance checks the behaviour shared by the caches of every eviction policy package, created through their factories.
+func TestConformance(t *testing.T) {
+
+	factories := []struct {
+		name    string
+		factory goria.CacheFactory[int, int]
+		cache   goria.Cache[int, int]
+	}{
+		{"lru", gorialru.Factory[int, int], (*gorialru.GoriaLRU[int, int])(nil)},
+		{"mru", goriamru.Factory[int, int], (*goriamru.GoriaMRU[int, int])(nil)},
+		{"lfu", gorialfu.Factory[int, int], (*gorialfu.GoriaLFU[int, int])(nil)},
+		{"arc", goriaarc.Factory[int, int], (*goriaarc.GoriaARC[int, int])(nil)},
+		{"2q", goria2q.Factory[int, int], (*goria2q.Goria2Q[int, int])(nil)},
+		{"2q ratios", goria2q.FactoryWithRatios[int, int](0.2, 0.4), (*goria2q.Goria2Q[int, int])(nil)},
+		{"slru", goriaslru.Factory[int, int], (*goriaslru.GoriaSLRU[int, int])(nil)},
+		{"slru ratio", goriaslru.FactoryWithProtectedRatio[int, int](0.5), (*goriaslru.GoriaSLRU[int, int])(nil)},
+		{"tinylfu", goriatinylfu.Factory[int, int], (*goriatinylfu.GoriaTinyLFU[int, int])(nil)},
+		{"fifo", goriafifo.Factory[int, int], (*goriafifo.GoriaFIFO[int, int])(nil)},
+		{"clock", goriaclock.Factory[int, int], (*goriaclock.GoriaClock[int, int])(nil)},
+		{"sieve", goriasieve.Factory[int, int], (*goriasieve.GoriaSieve[int, int])(nil)},
+		{"sampled", goriasampled.Factory[int, int], (*goriasampled.GoriaSampled[int, int])(nil)},
+		{"sampled size", goriasampled.FactoryWithSampleSize[int, int](3, 7), (*goriasampled.GoriaSampled[int, int])(nil)},
+		{"random", goriarandom.Factory[int, int], (*goriarandom.GoriaRandom[int, int])(nil)},
+		{"random seed", goriarandom.FactoryWithSeed[int, int](7), (*goriarandom.GoriaRandom[int, int])(nil)},
+		{"lirs", gorialirs.Factory[int, int], (*gorialirs.GoriaLIRS[int, int])(nil)},
+		{"lirs ratio", gorialirs.FactoryWithHIRRatio[int, int](0.1), (*gorialirs.GoriaLIRS[int, int])(nil)},
+		{"custom", goriacustom.Factory[int, int](newQueuePolicy), (*goriacustom.GoriaCustom[int, int])(nil)},
+	}
+
+	for _, f := range factories {
+		t.Run(f.name, func(t *testing.T) {
+
+			if _, err := f.factory("zero", goria.Configuration[int, int]{}); err == nil {
+				t.Fatalf("cache should be rejected with a zero size")
+			}
+
+			var evicted []int
+			m := goria.NewCacheManager()
+			l, err := goria.CreateCache(m, "sample", goria.Configuration[int, int]{
+				Size:             128,
+				EvictionCallback: func(key int, value int) { evicted = append(evicted, key) },
+				StatsEnabled:     true,
+				Factory:          f.factory,
+			})
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+
+			if reflect.TypeOf(l) != reflect.TypeOf(f.cache) {
+				t.Fatalf("Wrong cache %T", l)
+			}
+
+			for i := 0; i < 256; i++ {
+				l.Put(i, i)
+			}
+
+			if l.GetName() != "sample" {
+				t.Fatalf("Wrong name %v", l.GetName())
+			}
+
+			keys := l.Keys()
+			if l.Len() != 128 || len(keys) != 128 {
+				t.Fatalf("Wrong len %v or keys %v", l.Len(), len(keys))
+			}
+
+			for _, k := range keys {
+				if v, ok := l.Get(k); !ok || v != k {
+					t.Fatalf("wrong key: %v", k)
+				}
+			}
+
+			if len(evicted) != 128 || l.GetStats().Evictions != 128 || l.GetStats().Items != 128 {
+				t.Fatalf("Wrong evictions %v, Evictions stat %v or Items stat %v", len(evicted), l.GetStats().Evictions, l.GetStats().Items)
+			}
+
+			k := keys[len(keys)-1]
+
+			if result, _ := l.PutIfAbsent(k, 0); result {
+				t.Fatalf("key %v should be already be associated with a value", k)
+			}
+
+			if result, _ := l.Replace(k, k, -1); !result {
+				t.Fatalf("key %v should be replaced", k)
+			}
+
+			if result, _ := l.ReplaceWithKeyOnly(k, -2); !result {
+				t.Fatalf("key %v should be replaced", k)
+			}
+
+			if v, ok, _ := l.GetAndRemove(k); !ok || v != -2 {
+				t.Fatalf("key %v should be removed with a value of -2, got %v", k, v)
+			}
+
+			if l.ContainsKey(k) {
+				t.Fatalf("key %v should be removed", k)
+			}
+
+			l.Clear()
+
+			if l.Len() != 0 || len(l.Keys()) != 0 {
+				t.Fatalf("Wrong len %v", l.Len())
+			}
+
+			w, err := f.factory("weight", goria.Configuration[int, int]{
+				Size:         2,
+				MaxWeight:    6,
+				Weigher:      func(key int, value int) int64 { return int64(value) },
+				StatsEnabled: true,
+			})
+			if err != nil {
+				t.Fatalf("err: %v", err)
+			}
+
+			// 3 comes back once evicted, then the update of 7 makes the cache overweight
+			for _, k := range []int{3, 5, 7, 3} {
+				w.Put(k, 1)
+			}
+			if err := w.Put(7, 6); err != nil {
+				t.Fatalf("err: %v", err)
+			}
+
+			if v, ok := w.Get(7); !ok || v != 6 || w.Len() != 1 || w.GetStats().Weight != 6 {
+				t.Fatalf("the updated key should be kept and the others evicted, got %v with weight %v", w.Keys(), w.GetStats().Weight)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package goria
+
+// EventType tells what happened to the entry of a CacheEntryEvent.
+type EventType int
+
+const (
+	Created EventType = iota
+	Updated
+	Removed
+	Expired
+	Evicted
+)
+
+func (t EventType) String() string {
+	switch t {
+	case Created:
+		return "Created"
+	case Updated:
+		return "Updated"
+	case Removed:
+		return "Removed"
+	case Expired:
+		return "Expired"
+	case Evicted:
+		return "Evicted"
+	}
+	return "Unknown"
+}
+
+// CacheEntryEvent describes a change of a cache entry, in the spirit of the JSR 107 CacheEntryEvent.
+// Value is the new value for Created and Updated events, OldValue the previous value for Updated events;
+// for Removed, Expired and Evicted events both hold the value of the entry leaving the cache.
+type CacheEntryEvent[K comparable, V any] struct {
+	Type     EventType
+	Key      K
+	Value    V
+	OldValue V
+}
+
+// CacheEntryListener is invoked synchronously, while the cache lock is held, so it must not call back into the cache.
+type CacheEntryListener[K comparable, V any] func(event CacheEntryEvent[K, V])
+
+// CacheEntryEventFilter selects the events delivered to a CacheEntryListener.
+type CacheEntryEventFilter[K comparable, V any] func(event CacheEntryEvent[K, V]) bool
+
+// EventTypeFilter delivers only the events of the given types.
+func EventTypeFilter[K comparable, V any](types ...EventType) CacheEntryEventFilter[K, V] {
+	return func(event CacheEntryEvent[K, V]) bool {
+		for _, t := range types {
+			if event.Type == t {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,33 @@
+package goria
+
+import "errors"
+
+var ErrNoVictim = errors.New("The Goria Cache eviction policy has no victim to evict")
+
+// EvictionPolicy tracks the keys of a cache to decide which one is evicted when the cache is full.
+// OnInsert is called for every new key, OnAccess for every hit or update and OnRemove for
+// every key leaving the cache, whatever the reason, Victim returns the key to evict next.
+// evictable is false for the key whose insertion or update made the cache evict, if any, so that a key
+// is never evicted to make room for itself. Victim must return an evictable key of the cache whenever
+// the cache is over capacity, the write making the cache evict returns ErrNoVictim otherwise.
+// Eviction policies are invoked while the cache lock is held so they need no locking of their own.
+type EvictionPolicy[K comparable] interface {
+	OnAccess(key K)
+	OnInsert(key K)
+	OnRemove(key K)
+	Victim(evictable func(key K) bool) (K, bool)
+}
+
+// OrderedEvictionPolicy is implemented by the policies able to list their keys from the next victim on,
+// the keys of the other policies being returned in no particular order.
+type OrderedEvictionPolicy[K comparable] interface {
+	EvictionPolicy[K]
+	Keys() []K
+}
+
+// AdmissionEvictionPolicy is implemented by the policies filtering the keys entering the cache, Rejected reports
+// whether the key returned by the last call to Victim is a new key refused in favour of the cached ones.
+type AdmissionEvictionPolicy[K comparable] interface {
+	EvictionPolicy[K]
+	Rejected() bool
+}
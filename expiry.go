@@ -0,0 +1,65 @@
+package goria
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// Eternal is the duration of an entry that never expires.
+	Eternal time.Duration = math.MaxInt64
+	// Unchanged keeps the current expiry of an entry, it can be returned by ExpiryForAccess and ExpiryForUpdate.
+	Unchanged time.Duration = -1
+)
+
+// ExpiryPolicy tells for how long an entry lives after it has been created, accessed or updated,
+// in the spirit of the JSR 107 ExpiryPolicy.
+// A zero duration expires the entry immediately, a new entry with a zero duration is not stored at all.
+type ExpiryPolicy interface {
+	ExpiryForCreation() time.Duration
+	ExpiryForAccess() time.Duration
+	ExpiryForUpdate() time.Duration
+}
+
+type expiryPolicy struct {
+	creation time.Duration
+	access   time.Duration
+	update   time.Duration
+}
+
+func (p expiryPolicy) ExpiryForCreation() time.Duration {
+	return p.creation
+}
+
+func (p expiryPolicy) ExpiryForAccess() time.Duration {
+	return p.access
+}
+
+func (p expiryPolicy) ExpiryForUpdate() time.Duration {
+	return p.update
+}
+
+// NewCreatedExpiryPolicy expires entries d after their creation.
+func NewCreatedExpiryPolicy(d time.Duration) ExpiryPolicy {
+	return expiryPolicy{creation: d, access: Unchanged, update: Unchanged}
+}
+
+// NewAccessedExpiryPolicy expires entries d after their creation or their last access.
+func NewAccessedExpiryPolicy(d time.Duration) ExpiryPolicy {
+	return expiryPolicy{creation: d, access: d, update: Unchanged}
+}
+
+// NewModifiedExpiryPolicy expires entries d after their creation or their last update.
+func NewModifiedExpiryPolicy(d time.Duration) ExpiryPolicy {
+	return expiryPolicy{creation: d, access: Unchanged, update: d}
+}
+
+// NewTouchedExpiryPolicy expires entries d after their creation, their last access or their last update.
+func NewTouchedExpiryPolicy(d time.Duration) ExpiryPolicy {
+	return expiryPolicy{creation: d, access: d, update: d}
+}
+
+// NewEternalExpiryPolicy never expires entries, it is the default policy of every cache.
+func NewEternalExpiryPolicy() ExpiryPolicy {
+	return expiryPolicy{creation: Eternal, access: Unchanged, update: Unchanged}
+}
@@ -0,0 +1,49 @@
+/*
+Package goria defines the contract shared by every Goria cache, whatever its eviction policy, with an eye to JSR 107
+*/
+package goria
+
+import "time"
+
+// EvictionCallback is invoked with the key and the value of an entry evicted to make room for another one.
+type EvictionCallback[K comparable, V any] func(key K, value V)
+
+// Cache is the set of operations implemented by every Goria cache, so that
+// eviction policies can be swapped without touching call sites.
+// Every Goria cache is safe for concurrent use by multiple goroutines, the eviction
+// callback and the entry listeners are invoked while the cache lock is held so
+// they must not call back into the cache. The segments of a goriasharded cache
+// share them and invoke them concurrently, so there they must be safe for
+// concurrent use as well.
+type Cache[K comparable, V any] interface {
+	Put(key K, value V) error
+	PutWithTTL(key K, value V, ttl time.Duration) error
+	PutAll(m map[K]V) error
+	PutIfAbsent(key K, value V) (bool, error)
+	Get(key K) (value V, exists bool)
+	GetOrLoad(key K) (value V, exists bool, err error)
+	GetAll(keys []K) map[K]V
+	GetAllOrLoad(keys []K) (map[K]V, error)
+	LoadAll(keys []K, replaceExisting bool, completion CompletionListener)
+	Replace(key K, oldValue V, newValue V) (bool, error)
+	ReplaceWithKeyOnly(key K, newValue V) (bool, error)
+	GetAndReplace(key K, newValue V) (V, bool, error)
+	RemoveWithKeyOnly(key K) (bool, error)
+	Remove(key K, oldValue V) (bool, error)
+	RemoveAll(m map[K]V) error
+	RemoveAllWithoutParameters() error
+	GetAndRemove(key K) (V, bool, error)
+	Clear()
+	Invoke(key K, processor EntryProcessor[K, V], args ...interface{}) (interface{}, error)
+	InvokeAll(keys []K, processor EntryProcessor[K, V], args ...interface{}) map[K]EntryProcessorResult
+	RegisterCacheEntryListener(listener CacheEntryListener[K, V], filter CacheEntryEventFilter[K, V]) uint64
+	DeregisterCacheEntryListener(id uint64) bool
+	Keys() []K
+	ContainsKey(key K) bool
+	Len() int
+	GetName() string
+	IsStatsEnabled() bool
+	SetStatisticsEnabled(enabled bool)
+	ClearStatistics()
+	GetStats() CacheStats
+}
@@ -0,0 +1,175 @@
+/*
+Package goria2q provides the functionality of a 2Q Cache with an eye to JSR 107
+
+The 2Q policy of Johnson and Shasha puts new entries in the FIFO queue A1in,
+while the ghost queue A1out remembers the keys recently evicted from it. A key
+found in A1out has been used again soon after being evicted, so it enters the
+LRU list Am, from which it can only be evicted once A1in fits its share of the
+cache. A scan of keys used once therefore goes through A1in without evicting
+the entries of Am, and hits in A1in do not move the entries, so that the
+correlated references of a burst are not mistaken for frequency.
+*/
+package goria2q
+
+import (
+	"container/list"
+	"errors"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
+
+// DefaultInRatio and DefaultOutRatio are the sizes of A1in and A1out, relatively to the size of the cache,
+// used by New and NewWithConfiguration.
+const (
+	DefaultInRatio  = 0.25
+	DefaultOutRatio = 0.5
+)
+
+var ErrRatio = errors.New("The Goria Cache need an in ratio between 0 and 1 and a positive out ratio")
+
+type Goria2Q[K comparable, V any] struct {
+	*engine.Cache[K, V]
+}
+
+var _ goria.Cache[string, interface{}] = (*Goria2Q[string, interface{}])(nil)
+
+func New[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*Goria2Q[K, V], error) {
+	return NewWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
+}
+
+func NewWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*Goria2Q[K, V], error) {
+	return NewWithRatios(name, config, DefaultInRatio, DefaultOutRatio)
+}
+
+// NewWithRatios builds a cache whose A1in queue holds up to inRatio of its entries,
+// while A1out remembers up to outRatio of its size in evicted keys.
+func NewWithRatios[K comparable, V any](name string, config goria.Configuration[K, V], inRatio, outRatio float64) (*Goria2Q[K, V], error) {
+	if inRatio <= 0 || inRatio >= 1 || outRatio <= 0 {
+		return nil, ErrRatio
+	}
+	inSize := max(int(float64(config.Size)*inRatio), 1)
+	outSize := max(int(float64(config.Size)*outRatio), 1)
+	c, err := engine.New(name, config, newPolicy[K](inSize, outSize))
+	if err != nil {
+		return nil, err
+	}
+	return &Goria2Q[K, V]{c}, nil
+}
+
+// Factory is the goria.CacheFactory of the 2Q policy, to be used with a goria.CacheManager.
+func Factory[K comparable, V any](name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+	c, err := NewWithConfiguration(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// FactoryWithRatios returns the goria.CacheFactory of the 2Q policy with the given ratios.
+func FactoryWithRatios[K comparable, V any](inRatio, outRatio float64) goria.CacheFactory[K, V] {
+	return func(name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+		c, err := NewWithRatios(name, config, inRatio, outRatio)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+type node struct {
+	list    *list.List
+	element *list.Element
+}
+
+// policy holds the queues a1in and a1out and the list am, newest or most recently used at the front.
+// victim is the key returned by Victim, which goes to a1out when it is removed from a1in.
+type policy[K comparable] struct {
+	inSize    int
+	outSize   int
+	a1in      *list.List
+	a1out     *list.List
+	am        *list.List
+	nodes     map[K]*node
+	victim    K
+	hasVictim bool
+}
+
+func newPolicy[K comparable](inSize, outSize int) *policy[K] {
+	return &policy[K]{
+		inSize:  inSize,
+		outSize: outSize,
+		a1in:    list.New(),
+		a1out:   list.New(),
+		am:      list.New(),
+		nodes:   make(map[K]*node),
+	}
+}
+
+func (p *policy[K]) OnAccess(key K) {
+	if n := p.nodes[key]; n.list == p.am {
+		p.am.MoveToFront(n.element)
+	}
+}
+
+func (p *policy[K]) OnInsert(key K) {
+	if n, ghost := p.nodes[key]; ghost {
+		p.move(n, p.am)
+	} else {
+		p.nodes[key] = &node{p.a1in, p.a1in.PushFront(key)}
+	}
+}
+
+// OnRemove moves the victim to a1out when it comes from a1in, the other keys are forgotten.
+func (p *policy[K]) OnRemove(key K) {
+	n := p.nodes[key]
+	if p.hasVictim && p.victim == key && n.list == p.a1in {
+		p.move(n, p.a1out)
+		if p.a1out.Len() > p.outSize {
+			delete(p.nodes, p.a1out.Remove(p.a1out.Back()).(K))
+		}
+	} else {
+		n.list.Remove(n.element)
+		delete(p.nodes, key)
+	}
+	var zero K
+	p.victim, p.hasVictim = zero, false
+}
+
+// Victim returns the oldest evictable key of a1in when a1in exceeds its share of the cache, the least recently used
+// evictable key of am otherwise.
+func (p *policy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	first, second := p.am, p.a1in
+	if p.a1in.Len() > p.inSize {
+		first, second = p.a1in, p.am
+	}
+	for _, l := range []*list.List{first, second} {
+		for element := l.Back(); element != nil; element = element.Prev() {
+			if key := element.Value.(K); evictable(key) {
+				p.victim, p.hasVictim = key, true
+				return key, true
+			}
+		}
+	}
+	return
+}
+
+// Keys returns the keys of a1in, oldest first, then the keys of am, least recently used first.
+func (p *policy[K]) Keys() []K {
+	keys := make([]K, 0, p.a1in.Len()+p.am.Len())
+	for _, l := range []*list.List{p.a1in, p.am} {
+		for element := l.Back(); element != nil; element = element.Prev() {
+			keys = append(keys, element.Value.(K))
+		}
+	}
+	return keys
+}
+
+func (p *policy[K]) move(n *node, l *list.List) {
+	key := n.list.Remove(n.element)
+	n.list, n.element = l, l.PushFront(key)
+}
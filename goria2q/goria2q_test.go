@@ -0,0 +1,94 @@
+package goria2q
+
+import (
+	"testing"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/gorialru"
+)
+
+func TestGoriaRatios(t *testing.T) {
+
+	for _, ratios := range [][2]float64{{0, 0.5}, {1, 0.5}, {0.25, 0}, {0.25, -1}} {
+		if _, err := NewWithRatios("sample", goria.Configuration[int, int]{Size: 10}, ratios[0], ratios[1]); err != ErrRatio {
+			t.Fatalf("cache should be rejected with ratios %v, got %v", ratios, err)
+		}
+	}
+}
+
+func TestGoriaQueues(t *testing.T) {
+
+	l, err := NewWithRatios("sample", goria.Configuration[string, int]{Size: 4}, 0.5, 0.5)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i, k := range []string{"a", "b", "c", "d"} {
+		l.Put(k, i)
+	}
+
+	// a hit in A1in does not save a from being the oldest key of A1in
+	l.Get("a")
+	l.Put("e", 4)
+
+	if l.ContainsKey("a") {
+		t.Fatalf("the oldest key of A1in should be evicted, got %v", l.Keys())
+	}
+
+	// a is found in A1out, so it enters Am
+	l.Put("a", 0)
+
+	keys := l.Keys()
+	if keys[len(keys)-1] != "a" {
+		t.Fatalf("a should be in Am, got %v", keys)
+	}
+
+	for i, k := range []string{"f", "g", "h", "i"} {
+		l.Put(k, i)
+	}
+
+	if !l.ContainsKey("a") {
+		t.Fatalf("a should not be evicted while A1in exceeds its share, got %v", l.Keys())
+	}
+}
+
+func TestGoriaScan(t *testing.T) {
+
+	l, err := New[int, int]("2q", 100, nil, false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	lru, err := gorialru.New[int, int]("lru", 100, nil, false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for _, c := range []goria.Cache[int, int]{l, lru} {
+		// the hot keys are evicted from A1in by a first scan and enter Am when they are used again
+		for i := 0; i < 40; i++ {
+			c.Put(i, i)
+		}
+		for i := 100; i < 200; i++ {
+			c.Put(i, i)
+		}
+		for i := 0; i < 40; i++ {
+			if _, ok := c.Get(i); !ok {
+				c.Put(i, i)
+			}
+		}
+		for i := 1000; i < 2000; i++ {
+			c.Put(i, i)
+		}
+	}
+
+	for i := 0; i < 40; i++ {
+		if !l.ContainsKey(i) {
+			t.Fatalf("hot key %v should survive a scan", i)
+		}
+		if lru.ContainsKey(i) {
+			t.Fatalf("hot key %v should be flushed out of the LRU by a scan", i)
+		}
+	}
+}
@@ -1,16 +1,18 @@
-package goriatest
+package goria_test
 
 import (
 	"testing"
+	"time"
 
+	"github.com/oscerd/goria"
 	"github.com/oscerd/goria/gorialru"
 	"github.com/oscerd/goria/goriamru"
 )
 
 func TestGoria(t *testing.T) {
 
-	lru, err := gorialru.New("sample", 5, nil, true)
-	mru, err := goriamru.New("sample", 5, nil, true)
+	lru, err := gorialru.New[int, int]("sample", 5, nil, true)
+	mru, err := goriamru.New[int, int]("sample", 5, nil, true)
 
 	if err != nil {
 		t.Fatalf("err: %v", err)
@@ -37,3 +39,63 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong len %v", mru.Len())
 	}
 }
+
+func TestCacheInterface(t *testing.T) {
+
+	lru, err := gorialru.New[int, int]("lru", 5, nil, true)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	mru, err := goriamru.New[int, int]("mru", 5, nil, true)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for _, c := range []goria.Cache[int, int]{lru, mru} {
+		for i := 0; i < 10; i++ {
+			c.Put(i, i)
+		}
+
+		if c.Len() != 5 {
+			t.Fatalf("Wrong len %v for cache %v", c.Len(), c.GetName())
+		}
+
+		if c.GetStats().Evictions != 5 {
+			t.Fatalf("Wrong Evictions stat %v for cache %v", c.GetStats().Evictions, c.GetName())
+		}
+	}
+}
+
+func TestCacheStats(t *testing.T) {
+
+	var stats goria.CacheStats
+
+	if stats.HitPercentage() != 0 || stats.MissPercentage() != 0 || stats.AverageGetTime() != 0 {
+		t.Fatalf("the statistics of an unused cache should be 0")
+	}
+
+	stats = goria.CacheStats{
+		Gets:       4,
+		Hits:       3,
+		Miss:       1,
+		Puts:       2,
+		Removals:   5,
+		GetTime:    6 * time.Microsecond,
+		PutTime:    3 * time.Millisecond,
+		RemoveTime: 5 * time.Nanosecond,
+	}
+
+	if stats.HitPercentage() != 75 || stats.MissPercentage() != 25 {
+		t.Fatalf("Wrong hit percentage %v or miss percentage %v", stats.HitPercentage(), stats.MissPercentage())
+	}
+
+	if stats.AverageGetTime() != 1.5 || stats.AveragePutTime() != 1500 || stats.AverageRemoveTime() != 0.001 {
+		t.Fatalf("Wrong average times %v, %v and %v", stats.AverageGetTime(), stats.AveragePutTime(), stats.AverageRemoveTime())
+	}
+
+	interval := stats.Sub(goria.CacheStats{Items: 10, Gets: 1, Hits: 1, GetTime: time.Microsecond})
+
+	if interval.Gets != 3 || interval.Hits != 2 || interval.Miss != 1 || interval.Puts != 2 || interval.AverageGetTime() != 5.0/3 {
+		t.Fatalf("Wrong interval %+v", interval)
+	}
+}
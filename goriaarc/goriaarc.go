@@ -0,0 +1,181 @@
+/*
+Package goriaarc provides the functionality of an ARC Cache with an eye to JSR 107
+
+The Adaptive Replacement Cache of Megiddo and Modha keeps the entries used once
+in T1 and the entries used more than once in T2, while the ghost lists B1 and B2
+remember the keys recently evicted from them. A miss on a ghost key moves the
+target size of T1 towards recency or frequency, so that the cache tunes itself
+to the workload.
+*/
+package goriaarc
+
+import (
+	"container/list"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
+
+type GoriaARC[K comparable, V any] struct {
+	*engine.Cache[K, V]
+}
+
+var _ goria.Cache[string, interface{}] = (*GoriaARC[string, interface{}])(nil)
+
+func New[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*GoriaARC[K, V], error) {
+	return NewWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
+}
+
+func NewWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*GoriaARC[K, V], error) {
+	c, err := engine.New(name, config, newPolicy[K](config.Size))
+	if err != nil {
+		return nil, err
+	}
+	return &GoriaARC[K, V]{c}, nil
+}
+
+// Factory is the goria.CacheFactory of the ARC policy, to be used with a goria.CacheManager.
+func Factory[K comparable, V any](name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+	c, err := NewWithConfiguration(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// node locates a key in one of the four lists of the policy.
+type node struct {
+	list    *list.List
+	element *list.Element
+}
+
+// policy holds the resident lists t1 and t2 and the ghost lists b1 and b2, most recently used at the front,
+// target is the size of t1 the policy aims at. fromB2 tells whether the key inserted last came from b2,
+// victim is the key returned by Victim, which is the one moved to a ghost list when it is removed.
+type policy[K comparable] struct {
+	size      int
+	target    int
+	t1, t2    *list.List
+	b1, b2    *list.List
+	nodes     map[K]*node
+	fromB2    bool
+	victim    K
+	hasVictim bool
+}
+
+func newPolicy[K comparable](size int) *policy[K] {
+	return &policy[K]{
+		size:  size,
+		t1:    list.New(),
+		t2:    list.New(),
+		b1:    list.New(),
+		b2:    list.New(),
+		nodes: make(map[K]*node),
+	}
+}
+
+func (p *policy[K]) OnAccess(key K) {
+	p.fromB2 = false
+	p.moveToFront(p.nodes[key], p.t2)
+}
+
+func (p *policy[K]) OnInsert(key K) {
+	p.fromB2 = false
+	n, ghost := p.nodes[key]
+	switch {
+	case ghost && n.list == p.b1:
+		p.target = min(p.size, p.target+max(p.b2.Len()/p.b1.Len(), 1))
+		p.moveToFront(n, p.t2)
+	case ghost && n.list == p.b2:
+		p.target = max(0, p.target-max(p.b1.Len()/p.b2.Len(), 1))
+		p.fromB2 = true
+		p.moveToFront(n, p.t2)
+	default:
+		if p.t1.Len()+p.b1.Len() >= p.size {
+			p.dropBack(p.b1)
+		} else if p.t1.Len()+p.t2.Len()+p.b1.Len()+p.b2.Len() >= 2*p.size {
+			p.dropBack(p.b2)
+		}
+		p.nodes[key] = &node{p.t1, p.t1.PushFront(key)}
+	}
+}
+
+// OnRemove moves the victim to the ghost list of its resident list, the other keys are forgotten.
+func (p *policy[K]) OnRemove(key K) {
+	n := p.nodes[key]
+	if p.hasVictim && p.victim == key {
+		if n.list == p.t1 {
+			p.moveToFront(n, p.b1)
+		} else {
+			p.moveToFront(n, p.b2)
+		}
+		for p.t1.Len()+p.b1.Len() > p.size && p.b1.Len() > 0 {
+			p.dropBack(p.b1)
+		}
+		for p.t1.Len()+p.t2.Len()+p.b1.Len()+p.b2.Len() > 2*p.size && p.b2.Len() > 0 {
+			p.dropBack(p.b2)
+		}
+	} else {
+		n.list.Remove(n.element)
+		delete(p.nodes, key)
+	}
+	var zero K
+	p.victim, p.hasVictim = zero, false
+}
+
+// Victim returns the least recently used evictable key of t1 when t1 exceeds its target, of t2 otherwise.
+// The key being inserted, at the front of t1 or of t2 when it comes from a ghost list, is not counted in t1,
+// and fromB2 only matters while it is being inserted, an update having moved its key to the front of t2.
+func (p *policy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	t1 := p.t1.Len()
+	if front := p.t1.Front(); front != nil && !evictable(front.Value.(K)) {
+		t1--
+	}
+	fromB2 := false
+	if front := p.t2.Front(); front != nil && !evictable(front.Value.(K)) {
+		fromB2 = p.fromB2
+	}
+	first, second := p.t2, p.t1
+	if t1 >= 1 && (t1 > p.target || (fromB2 && t1 == p.target)) {
+		first, second = p.t1, p.t2
+	}
+	for _, l := range []*list.List{first, second} {
+		for element := l.Back(); element != nil; element = element.Prev() {
+			if key := element.Value.(K); evictable(key) {
+				p.victim, p.hasVictim = key, true
+				return key, true
+			}
+		}
+	}
+	return
+}
+
+// Keys returns the keys of t1 then the keys of t2, least recently used first.
+func (p *policy[K]) Keys() []K {
+	keys := make([]K, 0, p.t1.Len()+p.t2.Len())
+	for _, l := range []*list.List{p.t1, p.t2} {
+		for element := l.Back(); element != nil; element = element.Prev() {
+			keys = append(keys, element.Value.(K))
+		}
+	}
+	return keys
+}
+
+func (p *policy[K]) moveToFront(n *node, l *list.List) {
+	if n.list == l {
+		l.MoveToFront(n.element)
+		return
+	}
+	key := n.list.Remove(n.element)
+	n.list, n.element = l, l.PushFront(key)
+}
+
+func (p *policy[K]) dropBack(l *list.List) {
+	if back := l.Back(); back != nil {
+		delete(p.nodes, l.Remove(back).(K))
+	}
+}
@@ -0,0 +1,108 @@
+package goriaarc
+
+import (
+	"testing"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/gorialru"
+)
+
+func TestGoriaAdaptation(t *testing.T) {
+
+	l, err := New[string, int]("sample", 2, nil, false)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Put("a", 1)
+	l.Put("b", 2)
+	l.Get("a")
+
+	// a is in T2 and b in T1, c evicts b to the ghost list B1
+	l.Put("c", 3)
+
+	if l.ContainsKey("b") || !l.ContainsKey("a") || !l.ContainsKey("c") {
+		t.Fatalf("the key used once should be evicted, got %v", l.Keys())
+	}
+
+	// b is found in B1, the target of T1 grows to 1 so that a is evicted from T2 to B2
+	l.Put("b", 2)
+
+	if l.ContainsKey("a") || !l.ContainsKey("b") || !l.ContainsKey("c") {
+		t.Fatalf("the key of T2 should be evicted, got %v", l.Keys())
+	}
+
+	keys := l.Keys()
+	if len(keys) != 2 || keys[0] != "c" || keys[1] != "b" {
+		t.Fatalf("c should be in T1 and b in T2, got %v", keys)
+	}
+
+	// a is found in B2, the target of T1 shrinks back to 0 so that c is evicted from T1
+	l.Put("a", 1)
+
+	if l.ContainsKey("c") || !l.ContainsKey("a") || !l.ContainsKey("b") {
+		t.Fatalf("the key of T1 should be evicted, got %v", l.Keys())
+	}
+
+	keys = l.Keys()
+	if len(keys) != 2 || keys[0] != "b" || keys[1] != "a" {
+		t.Fatalf("a and b should be in T2, got %v", keys)
+	}
+}
+
+// trace mixes a working set used twice a round with scans of keys used once, which flush it out of an LRU.
+func trace() []int {
+	var keys []int
+	scan := 1000
+	for round := 0; round < 50; round++ {
+		for repeat := 0; repeat < 2; repeat++ {
+			for i := 0; i < 40; i++ {
+				keys = append(keys, i)
+			}
+		}
+		for i := 0; i < 80; i++ {
+			keys = append(keys, scan)
+			scan++
+		}
+	}
+	return keys
+}
+
+func hitRatio(c goria.Cache[int, int], keys []int) float64 {
+	hits := 0
+	for _, k := range keys {
+		if _, ok := c.Get(k); ok {
+			hits++
+		} else {
+			c.Put(k, k)
+		}
+	}
+	return float64(hits) / float64(len(keys))
+}
+
+func TestGoriaTrace(t *testing.T) {
+
+	arc, err := New[int, int]("arc", 100, nil, false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	lru, err := gorialru.New[int, int]("lru", 100, nil, false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	keys := trace()
+	arcRatio, lruRatio := hitRatio(arc, keys), hitRatio(lru, keys)
+
+	t.Logf("ARC hit ratio %v, LRU hit ratio %v", arcRatio, lruRatio)
+
+	if arcRatio <= lruRatio {
+		t.Fatalf("ARC hit ratio %v should beat the LRU one %v", arcRatio, lruRatio)
+	}
+
+	if arc.Len() != 100 {
+		t.Fatalf("Wrong len %v", arc.Len())
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Package goriaclock provides the functionality of a CLOCK Cache with an eye to JSR 107
+
+The CLOCK policy, also known as second chance, keeps the entries on a circle
+swept by a hand. A hit only sets the visited bit of the entry, when an entry has
+to be evicted the hand clears the visited bits it meets and evicts the first
+entry that was not visited since the previous sweep. A new entry is put just
+behind the hand, so that it is the last one the hand meets.
+*/
+package goriaclock
+
+import (
+	"container/list"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
+
+type GoriaClock[K comparable, V any] struct {
+	*engine.Cache[K, V]
+}
+
+var _ goria.Cache[string, interface{}] = (*GoriaClock[string, interface{}])(nil)
+
+func New[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*GoriaClock[K, V], error) {
+	return NewWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
+}
+
+func NewWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*GoriaClock[K, V], error) {
+	c, err := engine.New(name, config, newPolicy[K]())
+	if err != nil {
+		return nil, err
+	}
+	return &GoriaClock[K, V]{c}, nil
+}
+
+// Factory is the goria.CacheFactory of the CLOCK policy, to be used with a goria.CacheManager.
+func Factory[K comparable, V any](name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+	c, err := NewWithConfiguration(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type slot[K comparable] struct {
+	key     K
+	visited bool
+}
+
+// policy keeps the keys on a circle, the hand moving from the front to the back of the list and then
+// back to the front.
+type policy[K comparable] struct {
+	circle   *list.List
+	hand     *list.Element
+	elements map[K]*list.Element
+}
+
+func newPolicy[K comparable]() *policy[K] {
+	return &policy[K]{
+		circle:   list.New(),
+		elements: make(map[K]*list.Element),
+	}
+}
+
+func (p *policy[K]) OnAccess(key K) {
+	p.elements[key].Value.(*slot[K]).visited = true
+}
+
+func (p *policy[K]) OnInsert(key K) {
+	s := &slot[K]{key: key}
+	if p.hand == nil {
+		p.elements[key] = p.circle.PushBack(s)
+	} else {
+		p.elements[key] = p.circle.InsertBefore(s, p.hand)
+	}
+}
+
+func (p *policy[K]) OnRemove(key K) {
+	element := p.elements[key]
+	if element == p.hand {
+		p.hand = p.next(element)
+		if p.hand == element {
+			p.hand = nil
+		}
+	}
+	p.circle.Remove(element)
+	delete(p.elements, key)
+}
+
+// Victim sweeps the circle from the hand, clearing the visited bits, up to a key that was not visited.
+// The key being inserted is left out.
+func (p *policy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	if p.circle.Len() == 0 || (p.circle.Len() == 1 && !evictable(p.circle.Front().Value.(*slot[K]).key)) {
+		return
+	}
+	if p.hand == nil {
+		p.hand = p.circle.Front()
+	}
+	for {
+		s := p.hand.Value.(*slot[K])
+		if !s.visited && evictable(s.key) {
+			return s.key, true
+		}
+		s.visited = false
+		p.hand = p.next(p.hand)
+	}
+}
+
+// Keys returns the keys in the order the hand meets them.
+func (p *policy[K]) Keys() []K {
+	keys := make([]K, 0, p.circle.Len())
+	element := p.hand
+	if element == nil {
+		element = p.circle.Front()
+	}
+	for i := 0; i < p.circle.Len(); i++ {
+		keys = append(keys, element.Value.(*slot[K]).key)
+		element = p.next(element)
+	}
+	return keys
+}
+
+func (p *policy[K]) next(element *list.Element) *list.Element {
+	if next := element.Next(); next != nil {
+		return next
+	}
+	return p.circle.Front()
+}
@@ -0,0 +1,65 @@
+package goriaclock
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestGoriaSecondChance(t *testing.T) {
+
+	l, err := New[string, int]("sample", 3, nil, false)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Put("a", 1)
+	l.Put("b", 2)
+	l.Put("c", 3)
+
+	// a is given a second chance and b is evicted, d having been put behind the hand
+	l.Get("a")
+	l.Put("d", 4)
+
+	if l.ContainsKey("b") || !l.ContainsKey("a") {
+		t.Fatalf("the first key not visited should be evicted, got %v", l.Keys())
+	}
+
+	keys := l.Keys()
+	expected := []string{"c", "d", "a"}
+	for i, k := range expected {
+		if keys[i] != k {
+			t.Fatalf("Wrong keys %v, expected %v", keys, expected)
+		}
+	}
+
+	// every key is visited, the hand clears them all and comes back to c, skipping e which has just been put
+	l.Get("a")
+	l.Get("c")
+	l.Get("d")
+	l.Put("e", 5)
+
+	if l.ContainsKey("c") || !l.ContainsKey("a") || !l.ContainsKey("d") || !l.ContainsKey("e") {
+		t.Fatalf("c should be evicted after a full sweep, got %v", l.Keys())
+	}
+}
+
+func BenchmarkGoriaGet(b *testing.B) {
+	l, err := New[string, int]("sample", 8192, nil, false)
+	if err != nil {
+		b.Fatalf("err: %v", err)
+	}
+	keys := make([]string, 8192)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		l.Put(keys[i], i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			l.Get(keys[i%len(keys)])
+			i++
+		}
+	})
+}
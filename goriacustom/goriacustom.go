@@ -0,0 +1,46 @@
+/*
+Package goriacustom provides a Goria Cache evicting its entries through a
+goria.EvictionPolicy of your own, so that a new policy only has to choose the
+victims while the statistics, the callbacks, the expiry and the bulk operations
+are shared with the caches of the other Goria packages.
+*/
+package goriacustom
+
+import (
+	"errors"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
+
+var ErrNoPolicy = errors.New("The Goria Cache need an eviction policy")
+
+type GoriaCustom[K comparable, V any] struct {
+	*engine.Cache[K, V]
+}
+
+var _ goria.Cache[string, interface{}] = (*GoriaCustom[string, interface{}])(nil)
+
+// New builds a cache evicting the victims of policy, which must not be shared with another cache.
+func New[K comparable, V any](name string, config goria.Configuration[K, V], policy goria.EvictionPolicy[K]) (*GoriaCustom[K, V], error) {
+	if policy == nil {
+		return nil, ErrNoPolicy
+	}
+	c, err := engine.New(name, config, policy)
+	if err != nil {
+		return nil, err
+	}
+	return &GoriaCustom[K, V]{c}, nil
+}
+
+// Factory returns the goria.CacheFactory of the caches evicting through the policies built by newPolicy,
+// to be used with a goria.CacheManager. newPolicy is given the size of every cache built.
+func Factory[K comparable, V any](newPolicy func(size int) goria.EvictionPolicy[K]) goria.CacheFactory[K, V] {
+	return func(name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+		c, err := New(name, config, newPolicy(config.Size))
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
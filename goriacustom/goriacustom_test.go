@@ -0,0 +1,121 @@
+package goriacustom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oscerd/goria"
+)
+
+// smallestPolicy evicts the smallest key, the way a user defined policy would.
+type smallestPolicy struct {
+	keys     map[int]bool
+	accessed int
+}
+
+func newSmallestPolicy(size int) goria.EvictionPolicy[int] {
+	return &smallestPolicy{keys: make(map[int]bool, size)}
+}
+
+func (p *smallestPolicy) OnAccess(key int) {
+	p.accessed++
+}
+
+func (p *smallestPolicy) OnInsert(key int) {
+	p.keys[key] = true
+}
+
+func (p *smallestPolicy) OnRemove(key int) {
+	delete(p.keys, key)
+}
+
+func (p *smallestPolicy) Victim(evictable func(key int) bool) (key int, ok bool) {
+	for k := range p.keys {
+		if evictable(k) && (!ok || k < key) {
+			key, ok = k, true
+		}
+	}
+	return
+}
+
+func TestGoria(t *testing.T) {
+
+	if _, err := New[int, int]("sample", goria.Configuration[int, int]{Size: 10}, nil); err != ErrNoPolicy {
+		t.Fatalf("cache should be rejected without a policy, got %v", err)
+	}
+
+	if _, err := New("sample", goria.Configuration[int, int]{}, newSmallestPolicy(0)); err == nil {
+		t.Fatalf("cache should be rejected with a zero size")
+	}
+
+	var evicted []int
+	policy := newSmallestPolicy(10).(*smallestPolicy)
+	l, err := New("sample", goria.Configuration[int, int]{
+		Size:             10,
+		StatsEnabled:     true,
+		EvictionCallback: func(key int, value int) { evicted = append(evicted, key) },
+	}, policy)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for _, k := range []int{50, 40, 30, 20, 10, 60, 70, 80, 90, 100, 5, 1} {
+		l.Put(k, k)
+	}
+
+	if l.Len() != 10 || len(policy.keys) != 10 {
+		t.Fatalf("Wrong len %v or policy len %v", l.Len(), len(policy.keys))
+	}
+
+	// a key is never evicted to make room for itself, so 5 evicts 10 and 1 evicts 5
+	if len(evicted) != 2 || evicted[0] != 10 || evicted[1] != 5 {
+		t.Fatalf("Wrong evicted keys %v", evicted)
+	}
+
+	if v, ok := l.Get(1); !ok || v != 1 || policy.accessed != 1 {
+		t.Fatalf("key %v should be found and its access reported to the policy", 1)
+	}
+
+	if stats := l.GetStats(); stats.Evictions != 2 || stats.Items != 10 || stats.Hits != 1 {
+		t.Fatalf("Wrong stats %+v", stats)
+	}
+
+	l.PutWithTTL(200, 200, time.Hour)
+
+	if l.ContainsKey(1) || !l.ContainsKey(200) {
+		t.Fatalf("key %v should be evicted", 1)
+	}
+
+	l.Clear()
+
+	if l.Len() != 0 || len(policy.keys) != 0 {
+		t.Fatalf("Wrong len %v or policy len %v", l.Len(), len(policy.keys))
+	}
+}
+
+func TestGoriaManager(t *testing.T) {
+
+	m := goria.NewCacheManager()
+
+	factory := Factory[int, int](newSmallestPolicy)
+	c, err := goria.CreateCache(m, "smallest", goria.Configuration[int, int]{Size: 2, Factory: factory})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if _, ok := c.(*GoriaCustom[int, int]); !ok {
+		t.Fatalf("Wrong cache %v", c)
+	}
+
+	c.Put(2, 2)
+	c.Put(1, 1)
+	c.Put(3, 3)
+
+	if c.ContainsKey(1) || !c.ContainsKey(2) || !c.ContainsKey(3) {
+		t.Fatalf("Wrong keys %v", c.Keys())
+	}
+
+	if _, err := factory("other", goria.Configuration[int, int]{Size: 2}); err != nil {
+		t.Fatalf("every cache should get a policy of its own, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Package goriafifo provides the functionality of a FIFO Cache with an eye to JSR 107
+
+Entries are evicted in the order they were put, whatever their use, so that a
+hit does not touch the eviction order.
+*/
+package goriafifo
+
+import (
+	"container/list"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
+
+type GoriaFIFO[K comparable, V any] struct {
+	*engine.Cache[K, V]
+}
+
+var _ goria.Cache[string, interface{}] = (*GoriaFIFO[string, interface{}])(nil)
+
+func New[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*GoriaFIFO[K, V], error) {
+	return NewWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
+}
+
+func NewWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*GoriaFIFO[K, V], error) {
+	c, err := engine.New(name, config, newPolicy[K]())
+	if err != nil {
+		return nil, err
+	}
+	return &GoriaFIFO[K, V]{c}, nil
+}
+
+// Factory is the goria.CacheFactory of the FIFO policy, to be used with a goria.CacheManager.
+func Factory[K comparable, V any](name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+	c, err := NewWithConfiguration(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// policy keeps the keys in a queue, the newest at the front.
+type policy[K comparable] struct {
+	queue    *list.List
+	elements map[K]*list.Element
+}
+
+func newPolicy[K comparable]() *policy[K] {
+	return &policy[K]{
+		queue:    list.New(),
+		elements: make(map[K]*list.Element),
+	}
+}
+
+func (p *policy[K]) OnAccess(key K) {}
+
+func (p *policy[K]) OnInsert(key K) {
+	p.elements[key] = p.queue.PushFront(key)
+}
+
+func (p *policy[K]) OnRemove(key K) {
+	p.queue.Remove(p.elements[key])
+	delete(p.elements, key)
+}
+
+// Victim returns the oldest evictable key.
+func (p *policy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	for element := p.queue.Back(); element != nil; element = element.Prev() {
+		if key := element.Value.(K); evictable(key) {
+			return key, true
+		}
+	}
+	return
+}
+
+// Keys returns the keys oldest first.
+func (p *policy[K]) Keys() []K {
+	keys := make([]K, 0, p.queue.Len())
+	for element := p.queue.Back(); element != nil; element = element.Prev() {
+		keys = append(keys, element.Value.(K))
+	}
+	return keys
+}
@@ -0,0 +1,48 @@
+package goriafifo
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestGoriaOrder(t *testing.T) {
+
+	l, err := New[string, int]("sample", 3, nil, false)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Put("a", 1)
+	l.Put("b", 2)
+	l.Put("c", 3)
+
+	// hits and updates do not change the eviction order
+	l.Get("a")
+	l.Put("a", 10)
+	l.Put("d", 4)
+
+	if l.ContainsKey("a") || !l.ContainsKey("b") {
+		t.Fatalf("the oldest key should be evicted, got %v", l.Keys())
+	}
+}
+
+func BenchmarkGoriaGet(b *testing.B) {
+	l, err := New[string, int]("sample", 8192, nil, false)
+	if err != nil {
+		b.Fatalf("err: %v", err)
+	}
+	keys := make([]string, 8192)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		l.Put(keys[i], i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			l.Get(keys[i%len(keys)])
+			i++
+		}
+	})
+}
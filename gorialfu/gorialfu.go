@@ -0,0 +1,133 @@
+/*
+Package gorialfu provides the functionality of an LFU Cache with an eye to JSR 107
+
+The keys are kept in buckets of equal access frequency, so that a hit and an
+eviction take constant time. When several keys share the lowest frequency the
+least recently used of them is evicted, a key being used when it is put,
+updated or read. A key just put is never evicted to make room for itself, so
+that new keys can enter a cache full of frequently used ones.
+*/
+package gorialfu
+
+import (
+	"container/list"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
+
+type GoriaLFU[K comparable, V any] struct {
+	*engine.Cache[K, V]
+}
+
+var _ goria.Cache[string, interface{}] = (*GoriaLFU[string, interface{}])(nil)
+
+func New[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*GoriaLFU[K, V], error) {
+	return NewWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
+}
+
+func NewWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*GoriaLFU[K, V], error) {
+	c, err := engine.New(name, config, newPolicy[K]())
+	if err != nil {
+		return nil, err
+	}
+	return &GoriaLFU[K, V]{c}, nil
+}
+
+// Factory is the goria.CacheFactory of the LFU policy, to be used with a goria.CacheManager.
+func Factory[K comparable, V any](name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+	c, err := NewWithConfiguration(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// frequency is a bucket of the keys used count times, the most recently used at the front.
+type frequency[K comparable] struct {
+	count int
+	keys  *list.List
+}
+
+type node[K comparable] struct {
+	frequency *list.Element
+	element   *list.Element
+}
+
+// policy keeps its frequency buckets in ascending count order, a key moving to the next bucket on every use.
+type policy[K comparable] struct {
+	frequencies *list.List
+	nodes       map[K]*node[K]
+}
+
+func newPolicy[K comparable]() *policy[K] {
+	return &policy[K]{
+		frequencies: list.New(),
+		nodes:       make(map[K]*node[K]),
+	}
+}
+
+func (p *policy[K]) OnAccess(key K) {
+	n := p.nodes[key]
+	current := n.frequency
+	count := current.Value.(*frequency[K]).count + 1
+
+	next := current.Next()
+	if next == nil || next.Value.(*frequency[K]).count != count {
+		next = p.frequencies.InsertAfter(&frequency[K]{count, list.New()}, current)
+	}
+	p.unlink(n)
+	n.frequency = next
+	n.element = next.Value.(*frequency[K]).keys.PushFront(key)
+}
+
+func (p *policy[K]) OnInsert(key K) {
+	first := p.frequencies.Front()
+	if first == nil || first.Value.(*frequency[K]).count != 1 {
+		first = p.frequencies.PushFront(&frequency[K]{1, list.New()})
+	}
+	p.nodes[key] = &node[K]{first, first.Value.(*frequency[K]).keys.PushFront(key)}
+}
+
+func (p *policy[K]) OnRemove(key K) {
+	p.unlink(p.nodes[key])
+	delete(p.nodes, key)
+}
+
+// Victim returns the least recently used of the least frequently used keys, other than the key being inserted.
+// The key being inserted being the most recently used of its bucket, at most one key is skipped.
+func (p *policy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	for f := p.frequencies.Front(); f != nil; f = f.Next() {
+		back := f.Value.(*frequency[K]).keys.Back()
+		if k := back.Value.(K); evictable(k) {
+			return k, true
+		}
+		if previous := back.Prev(); previous != nil {
+			return previous.Value.(K), true
+		}
+	}
+	return
+}
+
+func (p *policy[K]) Keys() []K {
+	keys := make([]K, 0, len(p.nodes))
+	for f := p.frequencies.Front(); f != nil; f = f.Next() {
+		for element := f.Value.(*frequency[K]).keys.Back(); element != nil; element = element.Prev() {
+			keys = append(keys, element.Value.(K))
+		}
+	}
+	return keys
+}
+
+// unlink removes the key of n from its bucket, dropping the bucket when it is left empty.
+func (p *policy[K]) unlink(n *node[K]) {
+	f := n.frequency.Value.(*frequency[K])
+	f.keys.Remove(n.element)
+	if f.keys.Len() == 0 {
+		p.frequencies.Remove(n.frequency)
+	}
+}
@@ -0,0 +1,131 @@
+package gorialfu
+
+import (
+	"testing"
+
+	"github.com/oscerd/goria"
+)
+
+func TestGoria(t *testing.T) {
+
+	var evicted []int
+	l, err := New[int, int]("sample", 128, func(key int, value int) { evicted = append(evicted, key) }, true)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i := 0; i < 128; i++ {
+		l.Put(i, i)
+	}
+
+	for i := 0; i < 64; i++ {
+		l.Get(i)
+	}
+
+	for i := 128; i < 256; i++ {
+		l.Put(i, i)
+	}
+
+	if l.Len() != 128 {
+		t.Fatalf("Wrong len %v", l.Len())
+	}
+
+	for i := 0; i < 64; i++ {
+		if v, ok := l.Get(i); !ok || v != i {
+			t.Fatalf("frequently used key %v should not be evicted", i)
+		}
+	}
+
+	if v, ok := l.Get(255); !ok || v != 255 {
+		t.Fatalf("key %v should be in the cache", 255)
+	}
+
+	if len(evicted) != 128 || l.GetStats().Evictions != 128 || l.GetStats().Items != 128 {
+		t.Fatalf("Wrong evictions %v, Evictions stat %v or Items stat %v", len(evicted), l.GetStats().Evictions, l.GetStats().Items)
+	}
+
+	for _, k := range evicted {
+		if k < 64 {
+			t.Fatalf("frequently used key %v should not be evicted", k)
+		}
+	}
+}
+
+func TestGoriaTieBreak(t *testing.T) {
+
+	l, err := New[string, int]("sample", 3, nil, false)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Put("a", 1)
+	l.Put("b", 2)
+	l.Put("c", 3)
+
+	// a, b and c share a frequency of 1, a being the least recently used
+	l.Put("d", 4)
+
+	if l.ContainsKey("a") {
+		t.Fatalf("the least recently used of the least frequently used keys should be evicted")
+	}
+
+	// b and c now share a frequency of 2, d alone has a frequency of 1
+	l.Get("c")
+	l.Get("b")
+	l.Put("e", 5)
+
+	if l.ContainsKey("d") || !l.ContainsKey("b") || !l.ContainsKey("c") {
+		t.Fatalf("the least frequently used key should be evicted, got %v", l.Keys())
+	}
+
+	// e is used more than b and c and f has just been put, so the tie between b and c is broken by recency
+	l.Get("e")
+	l.Get("e")
+	l.Put("f", 6)
+
+	if l.ContainsKey("c") || !l.ContainsKey("b") || !l.ContainsKey("e") {
+		t.Fatalf("the least recently used of b and c should be evicted, got %v", l.Keys())
+	}
+
+	keys := l.Keys()
+	expected := []string{"f", "b", "e"}
+	if len(keys) != len(expected) {
+		t.Fatalf("Wrong keys %v", keys)
+	}
+	for i, k := range expected {
+		if keys[i] != k {
+			t.Fatalf("Wrong keys %v, expected %v", keys, expected)
+		}
+	}
+}
+
+func TestGoriaScan(t *testing.T) {
+
+	l, err := NewWithConfiguration("sample", goria.Configuration[int, int]{Size: 10, StatsEnabled: true})
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		l.Put(i, i)
+		l.Get(i)
+		l.Get(i)
+	}
+
+	for i := 100; i < 1000; i++ {
+		l.Put(i, i)
+	}
+
+	for i := 0; i < 5; i++ {
+		if !l.ContainsKey(i) {
+			t.Fatalf("hot key %v should survive a scan", i)
+		}
+	}
+
+	if !l.ContainsKey(999) {
+		t.Fatalf("the last scanned key should be in the cache")
+	}
+}
@@ -0,0 +1,255 @@
+/*
+Package gorialirs provides the functionality of a LIRS Cache with an eye to JSR 107
+
+The Low Inter-reference Recency Set policy of Jiang and Zhang ranks the entries
+by the recency of their last two uses rather than of the last one. Most of the
+cache holds the LIR entries, used again soon after their previous use, while a
+small share holds the HIR entries in a FIFO queue from which the victims are
+taken. The stack S orders the entries by recency, its bottom always being a LIR
+entry, and keeps the keys of recently evicted HIR entries: an HIR key used
+again while in S has a shorter reuse distance than the least recent LIR entry,
+so it becomes LIR and that entry becomes HIR. Loops and scans larger than the
+cache therefore keep hitting the LIR entries instead of flushing them out.
+*/
+package gorialirs
+
+import (
+	"container/list"
+	"errors"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
+
+// DefaultHIRRatio is the share of the cache holding HIR entries used by New and NewWithConfiguration.
+const DefaultHIRRatio = 0.01
+
+var ErrHIRRatio = errors.New("The Goria Cache need an HIR ratio between 0 and 1")
+
+type GoriaLIRS[K comparable, V any] struct {
+	*engine.Cache[K, V]
+}
+
+var _ goria.Cache[string, interface{}] = (*GoriaLIRS[string, interface{}])(nil)
+
+func New[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*GoriaLIRS[K, V], error) {
+	return NewWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
+}
+
+func NewWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*GoriaLIRS[K, V], error) {
+	return NewWithHIRRatio(name, config, DefaultHIRRatio)
+}
+
+// NewWithHIRRatio builds a cache holding up to hirRatio of its entries, and at least one, as HIR entries,
+// the stack S remembering up to as many evicted HIR keys as the cache holds entries.
+func NewWithHIRRatio[K comparable, V any](name string, config goria.Configuration[K, V], hirRatio float64) (*GoriaLIRS[K, V], error) {
+	if hirRatio <= 0 || hirRatio >= 1 {
+		return nil, ErrHIRRatio
+	}
+	hirSize := max(int(float64(config.Size)*hirRatio), 1)
+	c, err := engine.New(name, config, newPolicy[K](max(config.Size-hirSize, 1), config.Size))
+	if err != nil {
+		return nil, err
+	}
+	return &GoriaLIRS[K, V]{c}, nil
+}
+
+// Factory is the goria.CacheFactory of the LIRS policy, to be used with a goria.CacheManager.
+func Factory[K comparable, V any](name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+	c, err := NewWithConfiguration(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// FactoryWithHIRRatio returns the goria.CacheFactory of the LIRS policy with the given HIR ratio.
+func FactoryWithHIRRatio[K comparable, V any](hirRatio float64) goria.CacheFactory[K, V] {
+	return func(name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+		c, err := NewWithHIRRatio(name, config, hirRatio)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+type status int
+
+const (
+	lir status = iota
+	hirResident
+	hirNonResident
+)
+
+// node tells where a key is: stack is its element in S, queue its element in Q or, for a non-resident key,
+// in the list of the non-resident keys. Either may be nil.
+type node struct {
+	status status
+	stack  *list.Element
+	queue  *list.Element
+}
+
+// policy holds the stack S and the queue Q of the resident HIR keys, most recent at the front, and the
+// non-resident HIR keys still in S, most recently evicted at the front.
+// victim is the key returned by Victim, which stays in S as a non-resident key when it is evicted.
+type policy[K comparable] struct {
+	lirSize         int
+	nonResidentSize int
+	lirCount        int
+	stack           *list.List
+	queue           *list.List
+	nonResident     *list.List
+	nodes           map[K]*node
+	victim          K
+	hasVictim       bool
+}
+
+func newPolicy[K comparable](lirSize, nonResidentSize int) *policy[K] {
+	return &policy[K]{
+		lirSize:         lirSize,
+		nonResidentSize: nonResidentSize,
+		stack:           list.New(),
+		queue:           list.New(),
+		nonResident:     list.New(),
+		nodes:           make(map[K]*node),
+	}
+}
+
+// OnAccess moves key to the top of S, an HIR key already in S becoming LIR, one not in S moving to the end of Q.
+func (p *policy[K]) OnAccess(key K) {
+	n := p.nodes[key]
+	switch {
+	case n.status == lir:
+		p.stack.MoveToFront(n.stack)
+		p.prune()
+	case n.stack != nil:
+		p.queue.Remove(n.queue)
+		n.queue = nil
+		p.stack.MoveToFront(n.stack)
+		p.promote(n)
+	default:
+		n.stack = p.stack.PushFront(key)
+		p.queue.MoveToFront(n.queue)
+	}
+}
+
+// OnInsert makes key LIR while the LIR entries do not fill their share of the cache or when key is a non-resident
+// HIR key, the other keys entering S and Q as resident HIR keys.
+func (p *policy[K]) OnInsert(key K) {
+	if n, ghost := p.nodes[key]; ghost {
+		p.nonResident.Remove(n.queue)
+		n.queue = nil
+		p.stack.MoveToFront(n.stack)
+		p.promote(n)
+	} else if p.lirCount < p.lirSize {
+		p.nodes[key] = &node{lir, p.stack.PushFront(key), nil}
+		p.lirCount++
+	} else {
+		p.nodes[key] = &node{hirResident, p.stack.PushFront(key), p.queue.PushFront(key)}
+	}
+}
+
+// OnRemove keeps the victim in S as a non-resident key when it is a resident HIR key of S, the other keys are forgotten.
+func (p *policy[K]) OnRemove(key K) {
+	n := p.nodes[key]
+	if p.hasVictim && p.victim == key && n.status == hirResident && n.stack != nil {
+		p.queue.Remove(n.queue)
+		n.status, n.queue = hirNonResident, p.nonResident.PushFront(key)
+		if p.nonResident.Len() > p.nonResidentSize {
+			p.forget(p.nonResident.Back().Value.(K))
+		}
+	} else {
+		if n.status == lir {
+			p.lirCount--
+		}
+		p.forget(key)
+	}
+	p.prune()
+	var zero K
+	p.victim, p.hasVictim = zero, false
+}
+
+// Victim returns the oldest key of Q or, when every resident key is LIR, the least recent LIR key,
+// the key being inserted being left out.
+func (p *policy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	for element := p.queue.Back(); element != nil; element = element.Prev() {
+		if key := element.Value.(K); evictable(key) {
+			p.victim, p.hasVictim = key, true
+			return key, true
+		}
+	}
+	for element := p.stack.Back(); element != nil; element = element.Prev() {
+		if key := element.Value.(K); p.nodes[key].status == lir && evictable(key) {
+			p.victim, p.hasVictim = key, true
+			return key, true
+		}
+	}
+	return
+}
+
+// Keys returns the resident HIR keys, oldest first, then the LIR keys, least recent first.
+func (p *policy[K]) Keys() []K {
+	keys := make([]K, 0, p.queue.Len()+p.lirCount)
+	for element := p.queue.Back(); element != nil; element = element.Prev() {
+		keys = append(keys, element.Value.(K))
+	}
+	for element := p.stack.Back(); element != nil; element = element.Prev() {
+		if key := element.Value.(K); p.nodes[key].status == lir {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// promote makes an HIR key of S LIR, the least recent LIR key becoming a resident HIR key at the end of Q
+// when the LIR keys exceed their share of the cache.
+func (p *policy[K]) promote(n *node) {
+	n.status = lir
+	p.lirCount++
+	if p.lirCount > p.lirSize {
+		bottom := p.stack.Back()
+		key := p.stack.Remove(bottom).(K)
+		demoted := p.nodes[key]
+		demoted.status, demoted.stack, demoted.queue = hirResident, nil, p.queue.PushFront(key)
+		p.lirCount--
+	}
+	p.prune()
+}
+
+// prune removes the HIR keys from the bottom of S, so that its bottom is a LIR key, the non-resident keys being forgotten.
+func (p *policy[K]) prune() {
+	for bottom := p.stack.Back(); bottom != nil; bottom = p.stack.Back() {
+		key := bottom.Value.(K)
+		n := p.nodes[key]
+		if n.status == lir {
+			return
+		}
+		if n.status == hirNonResident {
+			p.forget(key)
+		} else {
+			p.stack.Remove(bottom)
+			n.stack = nil
+		}
+	}
+}
+
+// forget removes key from S, Q and the non-resident keys.
+func (p *policy[K]) forget(key K) {
+	n := p.nodes[key]
+	if n.stack != nil {
+		p.stack.Remove(n.stack)
+	}
+	if n.queue != nil {
+		if n.status == hirNonResident {
+			p.nonResident.Remove(n.queue)
+		} else {
+			p.queue.Remove(n.queue)
+		}
+	}
+	delete(p.nodes, key)
+}
@@ -0,0 +1,182 @@
+package gorialirs
+
+import (
+	"testing"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/gorialru"
+)
+
+func TestGoria(t *testing.T) {
+
+	var evicted []int
+	l, err := New[int, int]("sample", 128, func(key int, value int) { evicted = append(evicted, key) }, true)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for _, ratio := range []float64{0, 1, -0.5} {
+		if _, err := NewWithHIRRatio("sample", goria.Configuration[int, int]{Size: 10}, ratio); err != ErrHIRRatio {
+			t.Fatalf("cache should be rejected with an HIR ratio of %v, got %v", ratio, err)
+		}
+	}
+
+	for i := 0; i < 256; i++ {
+		l.Put(i, i)
+	}
+
+	if l.Len() != 128 || len(l.Keys()) != 128 {
+		t.Fatalf("Wrong len %v", l.Len())
+	}
+
+	// the first 127 keys fill the LIR entries, the following ones evict each other as HIR entries
+	if keys := l.Keys(); keys[0] != 255 || keys[1] != 0 || keys[127] != 126 {
+		t.Fatalf("Wrong keys %v", keys)
+	}
+
+	for i, k := range evicted {
+		if k != i+127 {
+			t.Fatalf("key %v should be evicted before key %v", i+127, k)
+		}
+	}
+
+	if len(evicted) != 128 || l.GetStats().Evictions != 128 || l.GetStats().Items != 128 {
+		t.Fatalf("Wrong evictions %v, Evictions stat %v or Items stat %v", len(evicted), l.GetStats().Evictions, l.GetStats().Items)
+	}
+}
+
+// TestGoriaStack follows a reference trace with two LIR entries and one HIR entry.
+func TestGoriaStack(t *testing.T) {
+
+	var evicted []string
+	l, err := NewWithHIRRatio("sample", goria.Configuration[string, int]{
+		Size:             3,
+		EvictionCallback: func(key string, value int) { evicted = append(evicted, key) },
+	}, 0.34)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	expect := func(step string, keys ...string) {
+		t.Helper()
+		got := l.Keys()
+		if len(got) != len(keys) {
+			t.Fatalf("%v: Wrong keys %v, expected %v", step, got, keys)
+		}
+		for i := range keys {
+			if got[i] != keys[i] {
+				t.Fatalf("%v: Wrong keys %v, expected %v", step, got, keys)
+			}
+		}
+	}
+
+	// a and b are LIR, c and d HIR, c being evicted but kept in S as a non-resident key
+	l.Put("a", 1)
+	l.Put("b", 2)
+	l.Put("c", 3)
+	l.Put("d", 4)
+	expect("d", "d", "a", "b")
+
+	// c is used again while in S, so it becomes LIR and a, the bottom of S, becomes HIR
+	l.Put("c", 3)
+	expect("c", "a", "b", "c")
+
+	if len(evicted) != 2 || evicted[0] != "c" || evicted[1] != "d" {
+		t.Fatalf("Wrong evicted keys %v", evicted)
+	}
+
+	// b moves to the top of S, pruning the non-resident d from the bottom
+	l.Get("b")
+	expect("b", "a", "c", "b")
+
+	// a is not in S any more, the first hit puts it back in S, the second one makes it LIR
+	l.Get("a")
+	expect("a", "a", "c", "b")
+	l.Get("a")
+	expect("a again", "c", "b", "a")
+
+	// c, HIR and out of S, is forgotten when evicted
+	l.Put("e", 5)
+	expect("e", "e", "b", "a")
+
+	l.Put("c", 3)
+	expect("c again", "c", "b", "a")
+
+	if len(evicted) != 4 || evicted[2] != "c" || evicted[3] != "e" {
+		t.Fatalf("Wrong evicted keys %v", evicted)
+	}
+}
+
+func TestGoriaNonResident(t *testing.T) {
+
+	p := newPolicy[int](2, 2)
+	resident := 0
+	for k := 1; k <= 10; k++ {
+		p.OnInsert(k)
+		resident++
+		if resident > 3 {
+			victim, _ := p.Victim(func(key int) bool { return key != k })
+			p.OnRemove(victim)
+			resident--
+		}
+	}
+
+	if p.nonResident.Len() != 2 || p.nonResident.Front().Value != 9 || p.nonResident.Back().Value != 8 {
+		t.Fatalf("the non-resident keys should be the last two evicted, got %v of them", p.nonResident.Len())
+	}
+
+	if len(p.nodes) != 5 || p.stack.Len() != 5 || p.queue.Len() != 1 || p.lirCount != 2 {
+		t.Fatalf("Wrong nodes %v, stack %v, queue %v or LIR count %v", len(p.nodes), p.stack.Len(), p.queue.Len(), p.lirCount)
+	}
+}
+
+// loop uses keys in a loop slightly larger than the cache, the worst case of an LRU.
+func loop() []int {
+	var keys []int
+	for round := 0; round < 20; round++ {
+		for i := 0; i < 110; i++ {
+			keys = append(keys, i)
+		}
+	}
+	return keys
+}
+
+func hitRatio(c goria.Cache[int, int], keys []int) float64 {
+	hits := 0
+	for _, k := range keys {
+		if _, ok := c.Get(k); ok {
+			hits++
+		} else {
+			c.Put(k, k)
+		}
+	}
+	return float64(hits) / float64(len(keys))
+}
+
+func TestGoriaLoop(t *testing.T) {
+
+	lirs, err := New[int, int]("lirs", 100, nil, false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	lru, err := gorialru.New[int, int]("lru", 100, nil, false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	keys := loop()
+	lirsRatio, lruRatio := hitRatio(lirs, keys), hitRatio(lru, keys)
+
+	t.Logf("LIRS hit ratio %v, LRU hit ratio %v", lirsRatio, lruRatio)
+
+	if lruRatio != 0 || lirsRatio < 0.8 {
+		t.Fatalf("LIRS hit ratio %v should beat the LRU one %v on a loop", lirsRatio, lruRatio)
+	}
+
+	if lirs.Len() != 100 {
+		t.Fatalf("Wrong len %v", lirs.Len())
+	}
+}
@@ -1,10 +1,16 @@
 package gorialru
 
-import "testing"
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/oscerd/goria"
+)
 
 func TestGoria(t *testing.T) {
 
-	l, err := New("sample", 128, nil, true)
+	l, err := New[int, int]("sample", 128, nil, true)
 
 	if err != nil {
 		t.Fatalf("err: %v", err)
@@ -43,7 +49,7 @@ func TestGoria(t *testing.T) {
 	key, value := 253, 22
 	otherKey, oldValue, newValue := 279, 22, 47
 
-	var result = l.PutIfAbsent(key, value)
+	var result, _ = l.PutIfAbsent(key, value)
 
 	if l.GetStats().Items != 128 {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
@@ -57,7 +63,7 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("key %v should be already be associated with a value", key)
 	}
 
-	result = l.PutIfAbsent(otherKey, value)
+	result, _ = l.PutIfAbsent(otherKey, value)
 
 	if l.GetStats().Items != 128 {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
@@ -73,7 +79,7 @@ func TestGoria(t *testing.T) {
 
 	l.Replace(otherKey, oldValue, newValue)
 
-	result = l.Replace(otherKey, newValue+1, newValue+2)
+	result, _ = l.Replace(otherKey, newValue+1, newValue+2)
 
 	if l.GetStats().Items != 128 {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
@@ -87,7 +93,7 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("key %v should not be replaced with a value %v, since it's current value is %v and not %v", otherKey, newValue+2, newValue, newValue+1)
 	}
 
-	result = l.ReplaceWithKeyOnly(otherKey, newValue+1)
+	result, _ = l.ReplaceWithKeyOnly(otherKey, newValue+1)
 
 	if !result {
 		t.Fatalf("key %v should be replaced with a value %v", otherKey, newValue+1)
@@ -107,13 +113,11 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("key %v should have a value of %v", otherKey, newValue)
 	}
 
-	res := l.evictionList.Front()
-
-	if res.Value.(*entry).value != newValue+1 {
-		t.Fatalf("key %v should have a value of %v instead has a value of %v", otherKey, newValue, res.Value.(*entry).value)
+	if keys := l.Keys(); keys[len(keys)-1] != otherKey {
+		t.Fatalf("key %v should be the most recently used instead of %v", otherKey, keys[len(keys)-1])
 	}
 
-	result = l.RemoveWithKeyOnly(otherKey)
+	result, _ = l.RemoveWithKeyOnly(otherKey)
 
 	if !result {
 		t.Fatalf("key %v should be removed", otherKey)
@@ -123,12 +127,12 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 130 {
+	if l.GetStats().Evictions != 129 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
 	otherKey, oldValue = 252, 252
-	result = l.Remove(otherKey, oldValue)
+	result, _ = l.Remove(otherKey, oldValue)
 
 	if !result {
 		t.Fatalf("key %v should be removed with a value %v", otherKey, oldValue)
@@ -138,42 +142,42 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 131 {
+	if l.GetStats().Evictions != 129 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
 	otherKey = 248
-	var getAndRemoveResult = l.GetAndRemove(otherKey)
+	var getAndRemoveResult, removed, _ = l.GetAndRemove(otherKey)
 
 	if getAndRemoveResult != 248 {
-		t.Fatalf("key %v should be removed with a value %v", otherKey)
+		t.Fatalf("key %v should be removed with a value %v", otherKey, 248)
 	}
 
 	if l.GetStats().Items != 125 {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 132 {
+	if l.GetStats().Evictions != 129 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
 	otherKey = 2900
-	getAndRemoveResult = l.GetAndRemove(otherKey)
+	getAndRemoveResult, removed, _ = l.GetAndRemove(otherKey)
 
-	if getAndRemoveResult != nil {
-		t.Fatalf("key %v should not be removed")
+	if removed {
+		t.Fatalf("key %v should not be removed", otherKey)
 	}
 
 	if l.GetStats().Items != 125 {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 132 {
+	if l.GetStats().Evictions != 129 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
 	otherKey, oldValue, newValue = 247, 247, 1200
-	var getAndReplaceResult = l.GetAndReplace(otherKey, newValue)
+	var getAndReplaceResult, replaced, _ = l.GetAndReplace(otherKey, newValue)
 
 	if getAndReplaceResult != 247 {
 		t.Fatalf("key %v should be replaced with an original value of %v", otherKey, oldValue)
@@ -183,14 +187,14 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 132 {
+	if l.GetStats().Evictions != 129 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
 	otherKey, oldValue, newValue = 2900, 247, 1200
-	getAndReplaceResult = l.GetAndReplace(otherKey, newValue)
+	getAndReplaceResult, replaced, _ = l.GetAndReplace(otherKey, newValue)
 
-	if getAndReplaceResult != nil {
+	if replaced {
 		t.Fatalf("key %v should not be replaced", otherKey)
 	}
 
@@ -198,11 +202,11 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 132 {
+	if l.GetStats().Evictions != 129 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
-	commits := map[interface{}]interface{}{
+	commits := map[int]int{
 		253: 24,
 		267: 22,
 		280: 21,
@@ -216,13 +220,11 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 132 {
+	if l.GetStats().Evictions != 129 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
-	returnedCommits := make(map[interface{}]interface{})
-
-	returnedCommits = l.GetAll(commits)
+	returnedCommits := l.GetAll([]int{253, 267, 280, 281})
 
 	for k, v := range commits {
 		if returnedCommits[k] != v {
@@ -242,7 +244,7 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 136 {
+	if l.GetStats().Evictions != 129 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
@@ -266,15 +268,23 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 264 {
+	if l.GetStats().Evictions != 129 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
-	if l.GetStats().Gets != 139 {
+	if l.GetStats().Removals != 135 {
+		t.Fatalf("Wrong Removals stat %v", l.GetStats().Removals)
+	}
+
+	if l.GetStats().Puts != 268 {
+		t.Fatalf("Wrong Puts stat %v", l.GetStats().Puts)
+	}
+
+	if l.GetStats().Gets != 143 {
 		t.Fatalf("Wrong Gets stat %v", l.GetStats().Gets)
 	}
 
-	if l.GetStats().Hits != 136 {
+	if l.GetStats().Hits != 140 {
 		t.Fatalf("Wrong Hits stat %v", l.GetStats().Hits)
 	}
 
@@ -293,3 +303,73 @@ func TestGoria(t *testing.T) {
 	}
 
 }
+
+func TestGoriaResize(t *testing.T) {
+
+	var evicted []int
+	l, err := New[int, int]("sample", 10, func(key int, value int) { evicted = append(evicted, key) }, true)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		l.Put(i, i)
+	}
+
+	for _, size := range []int{0, -1} {
+		if _, err := l.Resize(size); !errors.Is(err, goria.ErrInvalidSize) {
+			t.Fatalf("size %v should be rejected, got %v", size, err)
+		}
+	}
+
+	if n, err := l.Resize(20); err != nil || n != 0 || l.Len() != 10 {
+		t.Fatalf("growing the cache shouldn't evict, got %v evicted and %v", n, err)
+	}
+
+	n, err := l.Resize(4)
+	if err != nil || n != 6 || l.Len() != 4 || l.GetSize() != 4 {
+		t.Fatalf("Wrong evicted count %v, len %v or size %v: %v", n, l.Len(), l.GetSize(), err)
+	}
+
+	for i := range evicted {
+		if evicted[i] != i {
+			t.Fatalf("the least recently used key should be evicted first, got %v", evicted)
+		}
+	}
+
+	for i := 6; i < 10; i++ {
+		if !l.ContainsKey(i) {
+			t.Fatalf("key %v should be kept", i)
+		}
+	}
+
+	if l.GetStats().Evictions != 6 || l.GetStats().Items != 4 {
+		t.Fatalf("Wrong Evictions stat %v or Items stat %v", l.GetStats().Evictions, l.GetStats().Items)
+	}
+
+	l.Put(10, 10)
+
+	if l.Len() != 4 {
+		t.Fatalf("the new size should cap the cache, got len %v", l.Len())
+	}
+}
+
+func BenchmarkGoriaGet(b *testing.B) {
+	l, err := New[string, int]("sample", 8192, nil, false)
+	if err != nil {
+		b.Fatalf("err: %v", err)
+	}
+	keys := make([]string, 8192)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		l.Put(keys[i], i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			l.Get(keys[i%len(keys)])
+			i++
+		}
+	})
+}
@@ -0,0 +1,109 @@
+package goriametrics
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"sync"
+
+	"github.com/oscerd/goria"
+)
+
+// ExpvarName is the name of the expvar variable holding the statistics of the published caches, keyed by cache name.
+const ExpvarName = "goria"
+
+var ErrExpvarName = errors.New("The Goria Cache statistics can't be published, another expvar variable has their name")
+
+// published is the expvar variable of the published caches, read again every time /debug/vars is served.
+type published struct {
+	mu       sync.Mutex
+	caches   map[string]Source
+	managers []*goria.CacheManager
+}
+
+var vars = &published{caches: make(map[string]Source)}
+
+// expvarName is the name vars is published under, a variable so that the tests can take it.
+var expvarName = ExpvarName
+
+var publishLock sync.Mutex
+
+// publish publishes vars with expvar on the first call to Publish or PublishManager, so that importing the package
+// publishes nothing, returning ErrExpvarName when another variable is published under its name.
+func publish() error {
+	publishLock.Lock()
+	defer publishLock.Unlock()
+
+	switch expvar.Get(expvarName) {
+	case nil:
+		expvar.Publish(expvarName, vars)
+	case vars:
+	default:
+		return ErrExpvarName
+	}
+	return nil
+}
+
+// Publish publishes the statistics of c with expvar under its name, replacing a cache published under the same name.
+func Publish(c Source) error {
+	if err := publish(); err != nil {
+		return err
+	}
+	vars.mu.Lock()
+	defer vars.mu.Unlock()
+
+	vars.caches[c.GetName()] = c
+	return nil
+}
+
+// Unpublish stops publishing the cache published under name.
+func Unpublish(name string) {
+	vars.mu.Lock()
+	defer vars.mu.Unlock()
+
+	delete(vars.caches, name)
+}
+
+// PublishManager publishes the statistics of every cache of m with expvar, so that the caches created through m
+// are published as soon as they are created and the destroyed ones are not published any more.
+func PublishManager(m *goria.CacheManager) error {
+	if err := publish(); err != nil {
+		return err
+	}
+	vars.mu.Lock()
+	defer vars.mu.Unlock()
+
+	for _, published := range vars.managers {
+		if published == m {
+			return nil
+		}
+	}
+	vars.managers = append(vars.managers, m)
+	return nil
+}
+
+// String returns the statistics of the published caches as a JSON object keyed by cache name.
+func (p *published) String() string {
+	p.mu.Lock()
+	caches := make([]Source, 0, len(p.caches))
+	for _, c := range p.caches {
+		caches = append(caches, c)
+	}
+	managers := append([]*goria.CacheManager(nil), p.managers...)
+	p.mu.Unlock()
+
+	stats := make(map[string]goria.CacheStats)
+	for _, c := range caches {
+		stats[c.GetName()] = c.GetStats()
+	}
+	for _, m := range managers {
+		for name, s := range m.Stats() {
+			stats[name] = s
+		}
+	}
+	b, err := json.Marshal(stats)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
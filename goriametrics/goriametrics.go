@@ -0,0 +1,165 @@
+/*
+Package goriametrics exposes the statistics of Goria caches to Prometheus and expvar.
+
+A Handler serves the caches registered with it and the caches of the
+registered CacheManagers in the Prometheus text exposition format, every
+series being labelled by the name of its cache. Publish and PublishManager
+publish the statistics of caches under /debug/vars through the standard expvar
+package instead. Only the statistics of the caches whose statistics are enabled
+are meaningful, apart from the number of entries and the capacity which are
+always reported.
+*/
+package goriametrics
+
+import (
+	"bufio"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oscerd/goria"
+)
+
+// Source is the part of a goria.Cache the Handler needs, whatever its key and value types.
+type Source interface {
+	GetName() string
+	GetStats() goria.CacheStats
+}
+
+// Handler is an http.Handler serving the statistics of caches in the Prometheus text exposition format.
+type Handler struct {
+	mu       sync.Mutex
+	caches   map[string]Source
+	managers []*goria.CacheManager
+}
+
+func NewHandler() *Handler {
+	return &Handler{caches: make(map[string]Source)}
+}
+
+// Register adds c to the caches served by h, replacing a cache registered under the same name.
+func (h *Handler) Register(c Source) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.caches[c.GetName()] = c
+}
+
+// RegisterManager adds every cache of m to the caches served by h, including the caches created later on.
+func (h *Handler) RegisterManager(m *goria.CacheManager) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.managers = append(h.managers, m)
+}
+
+// ServeHTTP writes the statistics of every cache, ordered by cache name, a cache of a registered CacheManager
+// taking the place of a registered cache of the same name so that every series is written once.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	buffered := bufio.NewWriter(w)
+	write(buffered, h.stats())
+	buffered.Flush()
+}
+
+type namedStats struct {
+	name  string
+	stats goria.CacheStats
+}
+
+func (h *Handler) stats() []namedStats {
+	h.mu.Lock()
+	caches := make([]Source, 0, len(h.caches))
+	for _, c := range h.caches {
+		caches = append(caches, c)
+	}
+	managers := append([]*goria.CacheManager(nil), h.managers...)
+	h.mu.Unlock()
+
+	byName := make(map[string]goria.CacheStats)
+	for _, c := range caches {
+		byName[c.GetName()] = c.GetStats()
+	}
+	for _, m := range managers {
+		for name, stats := range m.Stats() {
+			byName[name] = stats
+		}
+	}
+	all := make([]namedStats, 0, len(byName))
+	for name, stats := range byName {
+		all = append(all, namedStats{name, stats})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].name < all[j].name })
+	return all
+}
+
+type metric struct {
+	name  string
+	kind  string
+	help  string
+	value func(s goria.CacheStats) int64
+}
+
+var metrics = []metric{
+	{"goria_cache_gets_total", "counter", "The number of reads of the cache.", func(s goria.CacheStats) int64 { return s.Gets }},
+	{"goria_cache_hits_total", "counter", "The number of reads finding their key.", func(s goria.CacheStats) int64 { return s.Hits }},
+	{"goria_cache_misses_total", "counter", "The number of reads not finding their key.", func(s goria.CacheStats) int64 { return s.Miss }},
+	{"goria_cache_evictions_total", "counter", "The number of entries evicted to make room for others.", func(s goria.CacheStats) int64 { return s.Evictions }},
+	{"goria_cache_items", "gauge", "The number of entries in the cache.", func(s goria.CacheStats) int64 { return s.Items }},
+	{"goria_cache_capacity", "gauge", "The number of entries the cache can hold.", func(s goria.CacheStats) int64 { return s.Capacity }},
+}
+
+type operation struct {
+	name      string
+	histogram func(s goria.CacheStats) goria.LatencyHistogram
+	total     func(s goria.CacheStats) time.Duration
+}
+
+var operations = []operation{
+	{"get", func(s goria.CacheStats) goria.LatencyHistogram { return s.GetLatency }, func(s goria.CacheStats) time.Duration { return s.GetTime }},
+	{"put", func(s goria.CacheStats) goria.LatencyHistogram { return s.PutLatency }, func(s goria.CacheStats) time.Duration { return s.PutTime }},
+	{"remove", func(s goria.CacheStats) goria.LatencyHistogram { return s.RemoveLatency }, func(s goria.CacheStats) time.Duration { return s.RemoveTime }},
+}
+
+const durationMetric = "goria_cache_operation_duration_seconds"
+
+func write(w *bufio.Writer, all []namedStats) {
+	for _, m := range metrics {
+		header(w, m.name, m.kind, m.help)
+		for _, c := range all {
+			w.WriteString(m.name + "{cache=\"" + escape(c.name) + "\"} " + strconv.FormatInt(m.value(c.stats), 10) + "\n")
+		}
+	}
+
+	header(w, durationMetric, "histogram", "The duration of the get, put and remove operations.")
+	for _, c := range all {
+		for _, op := range operations {
+			labels := "cache=\"" + escape(c.name) + "\",operation=\"" + op.name + "\""
+			histogram := op.histogram(c.stats)
+			cumulative := int64(0)
+			for i, bound := range goria.LatencyBuckets {
+				cumulative += histogram[i]
+				w.WriteString(durationMetric + "_bucket{" + labels + ",le=\"" + seconds(bound) + "\"} " + strconv.FormatInt(cumulative, 10) + "\n")
+			}
+			count := strconv.FormatInt(histogram.Count(), 10)
+			w.WriteString(durationMetric + "_bucket{" + labels + ",le=\"+Inf\"} " + count + "\n")
+			w.WriteString(durationMetric + "_sum{" + labels + "} " + seconds(op.total(c.stats)) + "\n")
+			w.WriteString(durationMetric + "_count{" + labels + "} " + count + "\n")
+		}
+	}
+}
+
+func header(w *bufio.Writer, name, kind, help string) {
+	w.WriteString("# HELP " + name + " " + help + "\n")
+	w.WriteString("# TYPE " + name + " " + kind + "\n")
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'g', -1, 64)
+}
+
+// escape escapes a label value as the exposition format requires.
+var escape = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace
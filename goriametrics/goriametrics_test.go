@@ -0,0 +1,203 @@
+package goriametrics
+
+import (
+	"encoding/json"
+	"expvar"
+	"flag"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/gorialru"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+type testSource struct {
+	name  string
+	stats goria.CacheStats
+}
+
+func (s testSource) GetName() string {
+	return s.name
+}
+
+func (s testSource) GetStats() goria.CacheStats {
+	return s.stats
+}
+
+func TestHandler(t *testing.T) {
+
+	users := goria.CacheStats{
+		Items:      3,
+		Capacity:   128,
+		Gets:       10,
+		Hits:       7,
+		Miss:       3,
+		Evictions:  2,
+		GetTime:    1500 * time.Microsecond,
+		PutTime:    20 * time.Millisecond,
+		RemoveTime: 0,
+	}
+	users.GetLatency.Observe(time.Microsecond)
+	users.GetLatency.Observe(3 * time.Microsecond)
+	users.GetLatency.Observe(1496 * time.Microsecond)
+	users.PutLatency.Observe(20 * time.Millisecond)
+
+	h := NewHandler()
+	h.Register(testSource{"users", users})
+	h.Register(testSource{`pages "v2"`, goria.CacheStats{Items: 1, Capacity: 10}})
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	if contentType := recorder.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "text/plain; version=0.0.4") {
+		t.Fatalf("Wrong content type %v", contentType)
+	}
+
+	golden := "testdata/metrics.golden"
+	if *update {
+		if err := os.WriteFile(golden, recorder.Body.Bytes(), 0644); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if recorder.Body.String() != string(expected) {
+		t.Fatalf("Wrong metrics, got\n%v\nexpected\n%v", recorder.Body.String(), string(expected))
+	}
+}
+
+func TestHandlerManager(t *testing.T) {
+
+	m := goria.NewCacheManager()
+	h := NewHandler()
+	h.RegisterManager(m)
+
+	c, err := goria.CreateCache(m, "sessions", goria.Configuration[string, int]{Size: 2, StatsEnabled: true, Factory: gorialru.Factory[string, int]})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// a cache registered on its own as well as through its manager is served once
+	h.Register(c)
+
+	quiet, err := goria.CreateCache(m, "quiet", goria.Configuration[string, int]{Size: 4, Factory: gorialru.Factory[string, int]})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	c.Put("a", 1)
+	c.Put("b", 2)
+	c.Put("c", 3)
+	c.Get("c")
+	c.Get("a")
+	quiet.Put("a", 1)
+
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	body := recorder.Body.String()
+
+	if n := strings.Count(body, `goria_cache_items{cache="sessions"}`); n != 1 {
+		t.Fatalf("the cache should be served once, got %v series\n%v", n, body)
+	}
+
+	for _, line := range []string{
+		`goria_cache_items{cache="quiet"} 1`,
+		`goria_cache_capacity{cache="quiet"} 4`,
+		`goria_cache_gets_total{cache="sessions"} 2`,
+		`goria_cache_hits_total{cache="sessions"} 1`,
+		`goria_cache_misses_total{cache="sessions"} 1`,
+		`goria_cache_evictions_total{cache="sessions"} 1`,
+		`goria_cache_items{cache="sessions"} 2`,
+		`goria_cache_capacity{cache="sessions"} 2`,
+		`goria_cache_operation_duration_seconds_count{cache="sessions",operation="put"} 3`,
+		`goria_cache_operation_duration_seconds_count{cache="sessions",operation="get"} 2`,
+	} {
+		if !strings.Contains(body, line+"\n") {
+			t.Fatalf("the metrics should contain %v, got\n%v", line, body)
+		}
+	}
+}
+
+func TestPublish(t *testing.T) {
+
+	if expvar.Get(ExpvarName) != nil {
+		t.Fatalf("nothing should be published before the first cache")
+	}
+
+	m := goria.NewCacheManager()
+	if err := PublishManager(m); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := PublishManager(m); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	managed, err := goria.CreateCache(m, "expvar-managed", goria.Configuration[int, int]{Size: 4, StatsEnabled: true, Factory: gorialru.Factory[int, int]})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	standalone, err := gorialru.New[string, int]("expvar-standalone", 8, nil, true)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := Publish(standalone); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	managed.Put(1, 1)
+	managed.Get(1)
+	standalone.Get("a")
+
+	recorder := httptest.NewRecorder()
+	expvar.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/debug/vars", nil))
+
+	var vars map[string]json.RawMessage
+	if err := json.Unmarshal(recorder.Body.Bytes(), &vars); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	var stats map[string]goria.CacheStats
+	if err := json.Unmarshal(vars[ExpvarName], &stats); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if stats["expvar-managed"].Hits != 1 || stats["expvar-managed"].Items != 1 || stats["expvar-standalone"].Miss != 1 {
+		t.Fatalf("Wrong published stats %+v", stats)
+	}
+
+	Unpublish("expvar-standalone")
+	m.DestroyCache("expvar-managed")
+
+	var remaining map[string]goria.CacheStats
+	if err := json.Unmarshal([]byte(expvar.Get(ExpvarName).String()), &remaining); err != nil || len(remaining) != 0 {
+		t.Fatalf("the caches shouldn't be published any more, got %+v and %v", remaining, err)
+	}
+}
+
+func TestPublishTaken(t *testing.T) {
+
+	defer func(name string) { expvarName = name }(expvarName)
+	expvarName = "goria-taken"
+	expvar.NewInt(expvarName)
+
+	c, err := gorialru.New[string, int]("expvar-taken", 8, nil, true)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if err := Publish(c); err != ErrExpvarName {
+		t.Fatalf("the cache shouldn't be published under a name taken by another variable, got %v", err)
+	}
+
+	if err := PublishManager(goria.NewCacheManager()); err != ErrExpvarName {
+		t.Fatalf("the manager shouldn't be published under a name taken by another variable, got %v", err)
+	}
+}
@@ -1,260 +1,93 @@
 /*
-Package Goria provides the functionality of an LRU Cache with an eye to JSR 107
+Package Goria provides the functionality of an MRU Cache with an eye to JSR 107
 */
 package goriamru
 
 import (
 	"container/list"
-	"errors"
-)
-
-type EvictionCallback func(key interface{}, value interface{})
-
-type GoriaMRU struct {
-	Name         string
-	Size         int
-	items        map[interface{}]*list.Element
-	evictionList *list.List
-	onEvict      EvictionCallback
-	statsEnabled bool
-	stats        CacheStats
-}
-
-type CacheStats struct {
-	Items     int64
-	Gets      int64
-	Hits      int64
-	Evictions int64
-	Miss      int64
-}
-
-type entry struct {
-	key   interface{}
-	value interface{}
-}
-
-func New(name string, size int, evictionC EvictionCallback, statsEnabled bool) (*GoriaMRU, error) {
-	if size <= 0 {
-		return nil, errors.New("The Goria Cache need a positive value as size")
-	}
-	c := &GoriaMRU{
-		Name:         name,
-		Size:         size,
-		evictionList: list.New(),
-		items:        make(map[interface{}]*list.Element),
-		onEvict:      evictionC,
-		statsEnabled: statsEnabled,
-		stats: CacheStats{
-			Items:     0,
-			Evictions: 0,
-			Gets:      0,
-			Hits:      0,
-			Miss:      0,
-		},
-	}
-	return c, nil
-}
-
-func (c *GoriaMRU) Put(key, value interface{}) {
-	if item, ok := c.items[key]; ok {
-		c.evictionList.MoveToFront(item)
-		item.Value.(*entry).value = value
-		return
-	}
-
-	item := &entry{key, value}
-	element := c.evictionList.PushFront(item)
-	c.items[key] = element
-
-	if c.evictionList.Len() > c.Size {
-		c.removeFromHead()
-	}
-
-	if c.IsStatsEnabled() {
-		c.stats.Items++
-	}
-}
-
-func (c *GoriaMRU) PutAll(m map[interface{}]interface{}) {
-	for key, value := range m {
-		c.Put(key, value)
-	}
-}
-
-func (c *GoriaMRU) PutIfAbsent(key, value interface{}) bool {
-	var element, exists = c.items[key]
-	if !exists && element == nil {
-
-		item := &entry{key, value}
-		element := c.evictionList.PushFront(item)
-		c.items[key] = element
-
-		if c.evictionList.Len() > c.Size {
-			c.removeFromHead()
-		}
-		if c.IsStatsEnabled() {
-			c.stats.Items++
-		}
-		return true
-	}
-	return false
-}
-
-func (c *GoriaMRU) Get(key interface{}) (value interface{}, exists bool) {
-
-	if c.IsStatsEnabled() {
-		c.stats.Gets++
-	}
-	if item, exists := c.items[key]; exists {
-		c.evictionList.MoveToFront(item)
-		if c.IsStatsEnabled() {
-			c.stats.Hits++
-		}
 
-		return item.Value.(*entry).value, true
-	}
-
-	if c.IsStatsEnabled() {
-		c.stats.Miss++
-	}
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
 
-	return
+type GoriaMRU[K comparable, V any] struct {
+	*engine.Cache[K, V]
 }
 
-func (c *GoriaMRU) GetAll(m map[interface{}]interface{}) map[interface{}]interface{} {
-	returnedMap := make(map[interface{}]interface{})
+var _ goria.Cache[string, interface{}] = (*GoriaMRU[string, interface{}])(nil)
 
-	for k := range m {
-		value, exists := c.Get(k)
-		if exists {
-			returnedMap[k] = value
-		}
-	}
-	return returnedMap
+func New[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*GoriaMRU[K, V], error) {
+	return NewWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
 }
 
-func (c *GoriaMRU) Replace(key, oldValue interface{}, newValue interface{}) bool {
-	var element, exists = c.items[key]
-	if exists && element.Value.(*entry).value == oldValue {
-		c.evictionList.MoveToFront(element)
-		element.Value.(*entry).value = newValue
-		return true
+func NewWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*GoriaMRU[K, V], error) {
+	c, err := engine.New(name, config, newPolicy[K]())
+	if err != nil {
+		return nil, err
 	}
-	return false
+	return &GoriaMRU[K, V]{c}, nil
 }
 
-func (c *GoriaMRU) ReplaceWithKeyOnly(key, newValue interface{}) bool {
-	var element, exists = c.items[key]
-	if exists && element != nil {
-		c.evictionList.MoveToFront(element)
-		element.Value.(*entry).value = newValue
-		return true
+// Factory is the goria.CacheFactory of the MRU policy, to be used with a goria.CacheManager.
+func Factory[K comparable, V any](name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+	c, err := NewWithConfiguration(name, config)
+	if err != nil {
+		return nil, err
 	}
-	return false
-}
-
-func (c *GoriaMRU) GetAndReplace(key interface{}, newValue interface{}) interface{} {
-	v, ok := c.Get(key)
-	if ok {
-		c.ReplaceWithKeyOnly(key, newValue)
-		return v
-	}
-	return nil
+	return c, nil
 }
 
-func (c *GoriaMRU) RemoveWithKeyOnly(key interface{}) bool {
-	if element, exists := c.items[key]; exists {
-		c.removeElement(element)
-		return true
-	}
-	return false
+// Resize changes the size of the cache, evicting at once the entries in excess through the eviction callback,
+// the most recently used first, and returning how many were evicted.
+func (c *GoriaMRU[K, V]) Resize(newSize int) (int, error) {
+	return engine.Resize(c.Cache, newSize)
 }
 
-func (c *GoriaMRU) Remove(key interface{}, oldValue interface{}) bool {
-	var element, exists = c.items[key]
-	if exists && element.Value.(*entry).value == oldValue {
-		c.removeElement(element)
-		return true
-	}
-	return false
+// policy keeps the keys in a list, the most recently used at the front.
+type policy[K comparable] struct {
+	list     *list.List
+	elements map[K]*list.Element
 }
 
-func (c *GoriaMRU) RemoveAll(m map[interface{}]interface{}) {
-	for key, value := range m {
-		c.Remove(key, value)
+func newPolicy[K comparable]() *policy[K] {
+	return &policy[K]{
+		list:     list.New(),
+		elements: make(map[K]*list.Element),
 	}
 }
 
-func (c *GoriaMRU) RemoveAllWithoutParameters() {
-	var keys = c.Keys()
-	for i := 0; i < len(keys); i++ {
-		c.RemoveWithKeyOnly(keys[i])
-	}
+func (p *policy[K]) OnAccess(key K) {
+	p.list.MoveToFront(p.elements[key])
 }
 
-func (c *GoriaMRU) GetAndRemove(key interface{}) interface{} {
-	v, ok := c.Get(key)
-	if ok {
-		c.RemoveWithKeyOnly(key)
-		return v
-	}
-	return nil
+func (p *policy[K]) OnInsert(key K) {
+	p.elements[key] = p.list.PushFront(key)
 }
 
-func (c *GoriaMRU) Keys() []interface{} {
-	keys := make([]interface{}, len(c.items))
-	i := 0
-	for ent := c.evictionList.Back(); ent != nil; ent = ent.Prev() {
-		keys[i] = ent.Value.(*entry).key
-		i++
-	}
-	return keys
+func (p *policy[K]) OnRemove(key K) {
+	p.list.Remove(p.elements[key])
+	delete(p.elements, key)
 }
 
-func (c *GoriaMRU) ContainsKey(key interface{}) bool {
-	for ent := c.evictionList.Back(); ent != nil; ent = ent.Prev() {
-		if ent.Value.(*entry).key == key {
-			return true
+// Victim returns the most recently used evictable key, the key inserted last being left out.
+func (p *policy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	for element := p.list.Front(); element != nil; element = element.Next() {
+		if key := element.Value.(K); evictable(key) {
+			return key, true
 		}
 	}
-	return false
-}
-
-func (c *GoriaMRU) Len() int {
-	return c.evictionList.Len()
-}
-
-func (c *GoriaMRU) GetName() string {
-	return c.Name
-}
-
-func (c *GoriaMRU) IsStatsEnabled() bool {
-	return c.statsEnabled
-}
-
-func (c *GoriaMRU) GetStats() CacheStats {
-	return c.stats
-}
-
-func (c *GoriaMRU) removeFromHead() {
-	element := c.evictionList.Front()
-
-	if element != nil {
-		c.removeElement(element)
-	}
+	return
 }
 
-func (c *GoriaMRU) removeElement(el *list.Element) {
-	c.evictionList.Remove(el)
-	entry := el.Value.(*entry)
-	delete(c.items, entry.key)
-
-	if c.onEvict != nil {
-		c.onEvict(entry.key, entry.value)
-	}
-
-	if c.IsStatsEnabled() {
-		c.stats.Evictions++
-		c.stats.Items--
+// Keys returns the keys most recently used first.
+func (p *policy[K]) Keys() []K {
+	keys := make([]K, 0, p.list.Len())
+	for element := p.list.Front(); element != nil; element = element.Next() {
+		keys = append(keys, element.Value.(K))
 	}
+	return keys
 }
@@ -1,13 +1,15 @@
 package goriamru
 
 import (
-	"fmt"
+	"errors"
 	"testing"
+
+	"github.com/oscerd/goria"
 )
 
 func TestGoria(t *testing.T) {
 
-	l, err := New("sample", 5, nil, true)
+	l, err := New[int, int]("sample", 5, nil, true)
 
 	if err != nil {
 		t.Fatalf("err: %v", err)
@@ -44,9 +46,9 @@ func TestGoria(t *testing.T) {
 	}
 
 	key, value := 1, 1
-	otherKey, oldValue, newValue := 4, 4, 47
+	otherKey, oldValue, newValue := 9, 9, 47
 
-	var result = l.PutIfAbsent(key, value)
+	var result, _ = l.PutIfAbsent(key, value)
 
 	if l.GetStats().Items != 5 {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
@@ -60,7 +62,7 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("key %v should be already be associated with a value", key)
 	}
 
-	result = l.PutIfAbsent(otherKey, value)
+	result, _ = l.PutIfAbsent(otherKey, value)
 
 	if l.GetStats().Items != 5 {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
@@ -76,7 +78,7 @@ func TestGoria(t *testing.T) {
 
 	l.Replace(otherKey, oldValue, newValue)
 
-	result = l.Replace(otherKey, newValue+1, newValue+2)
+	result, _ = l.Replace(otherKey, newValue+1, newValue+2)
 
 	if l.GetStats().Items != 5 {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
@@ -90,7 +92,7 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("key %v should not be replaced with a value %v, since it's current value is %v and not %v", otherKey, newValue+2, newValue, newValue+1)
 	}
 
-	result = l.ReplaceWithKeyOnly(otherKey, newValue+1)
+	result, _ = l.ReplaceWithKeyOnly(otherKey, newValue+1)
 
 	if !result {
 		t.Fatalf("key %v should be replaced with a value %v", otherKey, newValue+1)
@@ -110,15 +112,11 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("key %v should have a value of %v", otherKey, newValue)
 	}
 
-	res := l.evictionList.Front()
-
-	if res.Value.(*entry).value != newValue+1 {
-		fmt.Printf("%d\n", l.evictionList.Front().Value)
-		fmt.Printf("%d\n", l.evictionList.Back().Value)
-		t.Fatalf("key %v should have a value of %v instead has a value of %v", otherKey, newValue, res.Value.(*entry).value)
+	if keys := l.Keys(); keys[0] != otherKey {
+		t.Fatalf("key %v should be the most recently used instead of %v", otherKey, keys[0])
 	}
 
-	result = l.RemoveWithKeyOnly(otherKey)
+	result, _ = l.RemoveWithKeyOnly(otherKey)
 
 	if !result {
 		t.Fatalf("key %v should be removed", otherKey)
@@ -128,12 +126,12 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 6 {
+	if l.GetStats().Evictions != 5 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
 	otherKey, oldValue = 1, 1
-	result = l.Remove(otherKey, oldValue)
+	result, _ = l.Remove(otherKey, oldValue)
 
 	if !result {
 		t.Fatalf("key %v should be removed with a value %v", otherKey, oldValue)
@@ -143,7 +141,7 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 7 {
+	if l.GetStats().Evictions != 5 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
@@ -151,14 +149,14 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 7 {
+	if l.GetStats().Evictions != 5 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
 	otherKey = 2900
-	var getAndRemoveResult = l.GetAndRemove(otherKey)
+	var _, removed, _ = l.GetAndRemove(otherKey)
 
-	if getAndRemoveResult != nil {
+	if removed {
 		t.Fatalf("key %v should not be removed", otherKey)
 	}
 
@@ -166,12 +164,12 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 7 {
+	if l.GetStats().Evictions != 5 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
 	otherKey, oldValue, newValue = 3, 3, 1200
-	var getAndReplaceResult = l.GetAndReplace(otherKey, newValue)
+	var getAndReplaceResult, replaced, _ = l.GetAndReplace(otherKey, newValue)
 
 	if getAndReplaceResult != 3 {
 		t.Fatalf("key %v should be replaced with an original value of %v", otherKey, oldValue)
@@ -181,14 +179,14 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 7 {
+	if l.GetStats().Evictions != 5 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
 	otherKey, oldValue, newValue = 2900, 247, 1200
-	getAndReplaceResult = l.GetAndReplace(otherKey, newValue)
+	getAndReplaceResult, replaced, _ = l.GetAndReplace(otherKey, newValue)
 
-	if getAndReplaceResult != nil {
+	if replaced {
 		t.Fatalf("key %v should not be replaced", otherKey)
 	}
 
@@ -196,7 +194,7 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 7 {
+	if l.GetStats().Evictions != 5 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
@@ -206,15 +204,23 @@ func TestGoria(t *testing.T) {
 		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
 	}
 
-	if l.GetStats().Evictions != 10 {
+	if l.GetStats().Evictions != 5 {
 		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
 	}
 
-	if l.GetStats().Gets != 9 {
+	if l.GetStats().Removals != 5 {
+		t.Fatalf("Wrong Removals stat %v", l.GetStats().Removals)
+	}
+
+	if l.GetStats().Puts != 13 {
+		t.Fatalf("Wrong Puts stat %v", l.GetStats().Puts)
+	}
+
+	if l.GetStats().Gets != 13 {
 		t.Fatalf("Wrong Gets stat %v", l.GetStats().Gets)
 	}
 
-	if l.GetStats().Hits != 7 {
+	if l.GetStats().Hits != 11 {
 		t.Fatalf("Wrong Hits stat %v", l.GetStats().Hits)
 	}
 
@@ -227,3 +233,53 @@ func TestGoria(t *testing.T) {
 	}
 
 }
+
+func TestGoriaResize(t *testing.T) {
+
+	var evicted []int
+	l, err := New[int, int]("sample", 10, func(key int, value int) { evicted = append(evicted, key) }, true)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		l.Put(i, i)
+	}
+
+	for _, size := range []int{0, -1} {
+		if _, err := l.Resize(size); !errors.Is(err, goria.ErrInvalidSize) {
+			t.Fatalf("size %v should be rejected, got %v", size, err)
+		}
+	}
+
+	if n, err := l.Resize(20); err != nil || n != 0 || l.Len() != 10 {
+		t.Fatalf("growing the cache shouldn't evict, got %v evicted and %v", n, err)
+	}
+
+	n, err := l.Resize(4)
+	if err != nil || n != 6 || l.Len() != 4 || l.GetSize() != 4 {
+		t.Fatalf("Wrong evicted count %v, len %v or size %v: %v", n, l.Len(), l.GetSize(), err)
+	}
+
+	for i := range evicted {
+		if evicted[i] != 9-i {
+			t.Fatalf("the most recently used key should be evicted first, got %v", evicted)
+		}
+	}
+
+	for i := 0; i < 4; i++ {
+		if !l.ContainsKey(i) {
+			t.Fatalf("key %v should be kept", i)
+		}
+	}
+
+	if l.GetStats().Evictions != 6 || l.GetStats().Items != 4 {
+		t.Fatalf("Wrong Evictions stat %v or Items stat %v", l.GetStats().Evictions, l.GetStats().Items)
+	}
+
+	l.Put(10, 10)
+
+	if l.Len() != 4 {
+		t.Fatalf("the new size should cap the cache, got len %v", l.Len())
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Package goriarandom provides the functionality of a random eviction Cache with an eye to JSR 107
+
+The entry evicted is picked at random, so that neither a hit nor an eviction
+has to maintain any order of the entries.
+*/
+package goriarandom
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
+
+type GoriaRandom[K comparable, V any] struct {
+	*engine.Cache[K, V]
+}
+
+var _ goria.Cache[string, interface{}] = (*GoriaRandom[string, interface{}])(nil)
+
+func New[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*GoriaRandom[K, V], error) {
+	return NewWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
+}
+
+func NewWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*GoriaRandom[K, V], error) {
+	return NewWithSeed(name, config, time.Now().UnixNano())
+}
+
+// NewWithSeed builds a cache picking its victims with a random generator seeded with seed, so that the evictions can be reproduced.
+func NewWithSeed[K comparable, V any](name string, config goria.Configuration[K, V], seed int64) (*GoriaRandom[K, V], error) {
+	c, err := engine.New(name, config, newPolicy[K](seed))
+	if err != nil {
+		return nil, err
+	}
+	return &GoriaRandom[K, V]{c}, nil
+}
+
+// Factory is the goria.CacheFactory of the random policy, to be used with a goria.CacheManager.
+func Factory[K comparable, V any](name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+	c, err := NewWithConfiguration(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// FactoryWithSeed returns the goria.CacheFactory of the random policy with the given seed.
+func FactoryWithSeed[K comparable, V any](seed int64) goria.CacheFactory[K, V] {
+	return func(name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+		c, err := NewWithSeed(name, config, seed)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+// policy keeps the keys in a slice, a removed key being replaced by the last one.
+type policy[K comparable] struct {
+	rand    *rand.Rand
+	keys    []K
+	indexes map[K]int
+}
+
+func newPolicy[K comparable](seed int64) *policy[K] {
+	return &policy[K]{
+		rand:    rand.New(rand.NewSource(seed)),
+		indexes: make(map[K]int),
+	}
+}
+
+func (p *policy[K]) OnAccess(key K) {}
+
+func (p *policy[K]) OnInsert(key K) {
+	p.indexes[key] = len(p.keys)
+	p.keys = append(p.keys, key)
+}
+
+func (p *policy[K]) OnRemove(key K) {
+	i := p.indexes[key]
+	moved := p.keys[len(p.keys)-1]
+	p.keys[i] = moved
+	p.indexes[moved] = i
+	p.keys = p.keys[:len(p.keys)-1]
+	delete(p.indexes, key)
+}
+
+// Victim returns a random key, drawn again among the other keys when it draws the key being inserted or updated.
+func (p *policy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	n := len(p.keys)
+	if n == 0 || (n == 1 && !evictable(p.keys[0])) {
+		return
+	}
+	i := p.rand.Intn(n)
+	if !evictable(p.keys[i]) {
+		// the other n-1 keys are drawn from, skipping over i, so that each of them is as likely to be evicted
+		excluded := i
+		if i = p.rand.Intn(n - 1); i >= excluded {
+			i++
+		}
+	}
+	return p.keys[i], true
+}
@@ -0,0 +1,64 @@
+package goriarandom
+
+import (
+	"testing"
+
+	"github.com/oscerd/goria"
+)
+
+func TestGoriaSeed(t *testing.T) {
+
+	var evicted [3][]int
+	for i, seed := range []int64{42, 42, 43} {
+		l, err := NewWithSeed("sample", goria.Configuration[int, int]{
+			Size:             10,
+			EvictionCallback: func(key int, value int) { evicted[i] = append(evicted[i], key) },
+		}, seed)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		for k := 0; k < 100; k++ {
+			l.Put(k, k)
+		}
+	}
+
+	if len(evicted[0]) != 90 {
+		t.Fatalf("Wrong evictions %v", len(evicted[0]))
+	}
+
+	same := true
+	for i := range evicted[0] {
+		if evicted[0][i] != evicted[1][i] {
+			t.Fatalf("caches with the same seed should evict the same keys, got %v and %v", evicted[0], evicted[1])
+		}
+		same = same && evicted[0][i] == evicted[2][i]
+	}
+
+	if same {
+		t.Fatalf("caches with other seeds should evict other keys")
+	}
+}
+
+func TestGoriaUniform(t *testing.T) {
+
+	p := newPolicy[int](42)
+	for k := 0; k < 4; k++ {
+		p.OnInsert(k)
+	}
+
+	// 0 may not be evicted, the other keys should be drawn as often as each other
+	counts := make(map[int]int)
+	for i := 0; i < 30000; i++ {
+		key, ok := p.Victim(func(key int) bool { return key != 0 })
+		if !ok || key == 0 {
+			t.Fatalf("Wrong victim %v", key)
+		}
+		counts[key]++
+	}
+
+	for k := 1; k < 4; k++ {
+		if counts[k] < 9000 || counts[k] > 11000 {
+			t.Fatalf("every evictable key should be drawn about 10000 times, got %v", counts)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Package goriasampled provides the functionality of an approximated LRU Cache with an eye to JSR 107
+
+In the spirit of the allkeys-lru policy of Redis, no list of the entries is
+kept: every entry only records when it was last used, and the entry evicted is
+the least recently used among a few entries sampled at random. The larger the
+sample the closer the policy gets to an exact LRU, at the price of a slower
+eviction.
+*/
+package goriasampled
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
+
+// DefaultSampleSize is the number of entries sampled by New and NewWithConfiguration.
+const DefaultSampleSize = 5
+
+var ErrSampleSize = errors.New("The Goria Cache need a positive sample size")
+
+type GoriaSampled[K comparable, V any] struct {
+	*engine.Cache[K, V]
+}
+
+var _ goria.Cache[string, interface{}] = (*GoriaSampled[string, interface{}])(nil)
+
+func New[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*GoriaSampled[K, V], error) {
+	return NewWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
+}
+
+func NewWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*GoriaSampled[K, V], error) {
+	return NewWithSampleSize(name, config, DefaultSampleSize, time.Now().UnixNano())
+}
+
+// NewWithSampleSize builds a cache evicting the least recently used of sampleSize entries,
+// sampled by a random generator seeded with seed so that the evictions can be reproduced.
+func NewWithSampleSize[K comparable, V any](name string, config goria.Configuration[K, V], sampleSize int, seed int64) (*GoriaSampled[K, V], error) {
+	if sampleSize <= 0 {
+		return nil, ErrSampleSize
+	}
+	c, err := engine.New(name, config, newPolicy[K](sampleSize, seed))
+	if err != nil {
+		return nil, err
+	}
+	return &GoriaSampled[K, V]{c}, nil
+}
+
+// Factory is the goria.CacheFactory of the sampled LRU policy, to be used with a goria.CacheManager.
+func Factory[K comparable, V any](name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+	c, err := NewWithConfiguration(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// FactoryWithSampleSize returns the goria.CacheFactory of the sampled LRU policy with the given sample size and seed.
+func FactoryWithSampleSize[K comparable, V any](sampleSize int, seed int64) goria.CacheFactory[K, V] {
+	return func(name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+		c, err := NewWithSampleSize(name, config, sampleSize, seed)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+type slot[K comparable] struct {
+	key      K
+	lastUsed uint64
+}
+
+// policy keeps the keys in a slice, a removed key being replaced by the last one, and stamps
+// every use with clock.
+type policy[K comparable] struct {
+	sampleSize int
+	rand       *rand.Rand
+	slots      []slot[K]
+	indexes    map[K]int
+	clock      uint64
+}
+
+func newPolicy[K comparable](sampleSize int, seed int64) *policy[K] {
+	return &policy[K]{
+		sampleSize: sampleSize,
+		rand:       rand.New(rand.NewSource(seed)),
+		indexes:    make(map[K]int),
+	}
+}
+
+func (p *policy[K]) OnAccess(key K) {
+	p.clock++
+	p.slots[p.indexes[key]].lastUsed = p.clock
+}
+
+func (p *policy[K]) OnInsert(key K) {
+	p.clock++
+	p.indexes[key] = len(p.slots)
+	p.slots = append(p.slots, slot[K]{key, p.clock})
+}
+
+func (p *policy[K]) OnRemove(key K) {
+	i := p.indexes[key]
+	moved := p.slots[len(p.slots)-1]
+	p.slots[i] = moved
+	p.indexes[moved.key] = i
+	p.slots = p.slots[:len(p.slots)-1]
+	delete(p.indexes, key)
+}
+
+// Victim returns the least recently used of sampleSize keys sampled with replacement.
+func (p *policy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	var oldest *slot[K]
+	for i := 0; i < p.sampleSize; i++ {
+		s := p.sample(evictable)
+		if s != nil && (oldest == nil || s.lastUsed < oldest.lastUsed) {
+			oldest = s
+		}
+	}
+	if oldest == nil {
+		return
+	}
+	return oldest.key, true
+}
+
+// sample returns a random slot other than the one of the key being inserted or updated, nil when there is none.
+func (p *policy[K]) sample(evictable func(key K) bool) *slot[K] {
+	n := len(p.slots)
+	if n == 0 || (n == 1 && !evictable(p.slots[0].key)) {
+		return nil
+	}
+	i := p.rand.Intn(n)
+	if !evictable(p.slots[i].key) {
+		// the other n-1 slots are drawn from, skipping over i, so that each of them is as likely to be sampled
+		excluded := i
+		if i = p.rand.Intn(n - 1); i >= excluded {
+			i++
+		}
+	}
+	return &p.slots[i]
+}
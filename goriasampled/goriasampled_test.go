@@ -0,0 +1,96 @@
+package goriasampled
+
+import (
+	"testing"
+
+	"github.com/oscerd/goria"
+)
+
+func TestGoriaSampleSize(t *testing.T) {
+
+	if _, err := NewWithSampleSize("sample", goria.Configuration[int, int]{Size: 10}, 0, 1); err != ErrSampleSize {
+		t.Fatalf("cache should be rejected with a zero sample size, got %v", err)
+	}
+}
+
+func TestGoriaSeed(t *testing.T) {
+
+	var evicted [2][]int
+	for i := range evicted {
+		l, err := NewWithSampleSize("sample", goria.Configuration[int, int]{
+			Size:             10,
+			EvictionCallback: func(key int, value int) { evicted[i] = append(evicted[i], key) },
+		}, 3, 42)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		for k := 0; k < 100; k++ {
+			l.Put(k, k)
+			l.Get(k / 2)
+		}
+	}
+
+	if len(evicted[0]) != 90 {
+		t.Fatalf("Wrong evictions %v", len(evicted[0]))
+	}
+
+	for i := range evicted[0] {
+		if evicted[0][i] != evicted[1][i] {
+			t.Fatalf("caches with the same seed should evict the same keys, got %v and %v", evicted[0], evicted[1])
+		}
+	}
+}
+
+func TestGoriaSampling(t *testing.T) {
+
+	l, err := NewWithSampleSize("sample", goria.Configuration[int, int]{Size: 8}, 256, 7)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i := 0; i < 8; i++ {
+		l.Put(i, i)
+	}
+
+	// with a sample much larger than the cache, the least recently used key is evicted
+	for i := 7; i > 0; i-- {
+		l.Get(i)
+	}
+
+	l.Put(8, 8)
+
+	if l.ContainsKey(0) {
+		t.Fatalf("the least recently used key should be evicted, got %v", l.Keys())
+	}
+
+	l.Put(9, 9)
+
+	if l.ContainsKey(7) || !l.ContainsKey(8) {
+		t.Fatalf("the least recently used key should be evicted, got %v", l.Keys())
+	}
+}
+
+func TestGoriaUniform(t *testing.T) {
+
+	p := newPolicy[int](1, 42)
+	for k := 0; k < 4; k++ {
+		p.OnInsert(k)
+	}
+
+	// 0 may not be evicted, the other keys should be sampled as often as each other
+	counts := make(map[int]int)
+	for i := 0; i < 30000; i++ {
+		s := p.sample(func(key int) bool { return key != 0 })
+		if s == nil || s.key == 0 {
+			t.Fatalf("Wrong sample %v", s)
+		}
+		counts[s.key]++
+	}
+
+	for k := 1; k < 4; k++ {
+		if counts[k] < 9000 || counts[k] > 11000 {
+			t.Fatalf("every evictable key should be sampled about 10000 times, got %v", counts)
+		}
+	}
+}
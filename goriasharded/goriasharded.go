@@ -0,0 +1,420 @@
+/*
+Package goriasharded provides a Goria Cache partitioning its keys by hash across
+independently locked segments, so that goroutines working on different keys do
+not contend on a single lock.
+
+Every segment is a cache of its own, built through the Factory of the
+configuration, so any eviction policy can be sharded. The policy is applied per
+segment: an LRU GoriaSharded evicts the least recently used entry of the segment
+the new key falls in, not of the whole cache.
+
+The EvictionCallback, the entry listeners, the Weigher and the MetricsRecorder of
+the configuration are shared by every segment, each invoking them while holding
+its own lock, so unlike those of a single cache they run concurrently from
+different goroutines and must be safe for concurrent use.
+
+The maximum weight is split evenly across the segments as well, so an entry
+heavier than MaxWeight / shards is rejected with goria.ErrEntryTooHeavy even
+though it is lighter than MaxWeight.
+*/
+package goriasharded
+
+import (
+	"errors"
+	"hash/maphash"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/keyhash"
+)
+
+type GoriaSharded[K comparable, V any] struct {
+	Name         string
+	size         int
+	seed         maphash.Seed
+	shards       []goria.Cache[K, V]
+	statsEnabled atomic.Bool
+	lock         sync.Mutex
+	listeners    map[uint64][]uint64
+	listenerID   uint64
+}
+
+var _ goria.Cache[string, interface{}] = (*GoriaSharded[string, interface{}])(nil)
+
+// New builds a cache of config.Size entries split across shards segments built through config.Factory,
+// every segment holding up to config.Size / shards entries and config.MaxWeight / shards of weight, rounded up,
+// which is then the maximum weight of a single entry.
+// The MetricsRecorder of config is given the operations of every segment under name.
+func New[K comparable, V any](name string, shards int, config goria.Configuration[K, V]) (*GoriaSharded[K, V], error) {
+	if shards <= 0 {
+		return nil, errors.New("The Goria Cache need a positive number of shards")
+	}
+	if config.Factory == nil {
+		return nil, goria.ErrNoFactory
+	}
+	segment := config
+	segment.Size = (config.Size + shards - 1) / shards
+	segment.MaxWeight = (config.MaxWeight + int64(shards) - 1) / int64(shards)
+	if config.MetricsRecorder != nil {
+		segment.MetricsRecorder = namedRecorder{config.MetricsRecorder, name}
+	}
+	c := &GoriaSharded[K, V]{
+		Name:      name,
+		size:      config.Size,
+		seed:      maphash.MakeSeed(),
+		shards:    make([]goria.Cache[K, V], shards),
+		listeners: make(map[uint64][]uint64),
+	}
+	c.statsEnabled.Store(config.StatsEnabled)
+	for i := range c.shards {
+		shard, err := config.Factory(name+"-"+strconv.Itoa(i), segment)
+		if err != nil {
+			return nil, err
+		}
+		c.shards[i] = shard
+	}
+	return c, nil
+}
+
+// Factory returns the goria.CacheFactory of a cache split across shards segments built through segment,
+// to be used with a goria.CacheManager.
+func Factory[K comparable, V any](shards int, segment goria.CacheFactory[K, V]) goria.CacheFactory[K, V] {
+	return func(name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+		config.Factory = segment
+		c, err := New(name, shards, config)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+func (c *GoriaSharded[K, V]) Put(key K, value V) error {
+	return c.shard(key).Put(key, value)
+}
+
+func (c *GoriaSharded[K, V]) PutWithTTL(key K, value V, ttl time.Duration) error {
+	return c.shard(key).PutWithTTL(key, value, ttl)
+}
+
+// PutAll stores the entries segment by segment, the first error is returned and the following segments left untouched.
+func (c *GoriaSharded[K, V]) PutAll(m map[K]V) error {
+	for i, entries := range c.partitionMap(m) {
+		if len(entries) == 0 {
+			continue
+		}
+		if err := c.shards[i].PutAll(entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *GoriaSharded[K, V]) PutIfAbsent(key K, value V) (bool, error) {
+	return c.shard(key).PutIfAbsent(key, value)
+}
+
+func (c *GoriaSharded[K, V]) Get(key K) (value V, exists bool) {
+	return c.shard(key).Get(key)
+}
+
+func (c *GoriaSharded[K, V]) GetOrLoad(key K) (value V, exists bool, err error) {
+	return c.shard(key).GetOrLoad(key)
+}
+
+func (c *GoriaSharded[K, V]) GetAll(keys []K) map[K]V {
+	returnedMap, _ := c.GetAllOrLoad(keys)
+	return returnedMap
+}
+
+// GetAllOrLoad behaves like GetAll, returning the first error of the CacheLoader among the segments.
+func (c *GoriaSharded[K, V]) GetAllOrLoad(keys []K) (map[K]V, error) {
+	returnedMap := make(map[K]V)
+	var firstErr error
+	for i, shardKeys := range c.partition(keys) {
+		if len(shardKeys) == 0 {
+			continue
+		}
+		values, err := c.shards[i].GetAllOrLoad(shardKeys)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		for k, v := range values {
+			returnedMap[k] = v
+		}
+	}
+	return returnedMap, firstErr
+}
+
+// LoadAll asynchronously loads keys in every segment, completion is invoked once with the first error
+// when all the segments are done. completion may be nil.
+func (c *GoriaSharded[K, V]) LoadAll(keys []K, replaceExisting bool, completion goria.CompletionListener) {
+	var wg sync.WaitGroup
+	var once sync.Once
+	var firstErr error
+	for i, shardKeys := range c.partition(keys) {
+		if len(shardKeys) == 0 {
+			continue
+		}
+		wg.Add(1)
+		c.shards[i].LoadAll(shardKeys, replaceExisting, func(err error) {
+			if err != nil {
+				once.Do(func() { firstErr = err })
+			}
+			wg.Done()
+		})
+	}
+	go func() {
+		wg.Wait()
+		if completion != nil {
+			completion(firstErr)
+		}
+	}()
+}
+
+func (c *GoriaSharded[K, V]) Replace(key K, oldValue V, newValue V) (bool, error) {
+	return c.shard(key).Replace(key, oldValue, newValue)
+}
+
+func (c *GoriaSharded[K, V]) ReplaceWithKeyOnly(key K, newValue V) (bool, error) {
+	return c.shard(key).ReplaceWithKeyOnly(key, newValue)
+}
+
+func (c *GoriaSharded[K, V]) GetAndReplace(key K, newValue V) (V, bool, error) {
+	return c.shard(key).GetAndReplace(key, newValue)
+}
+
+func (c *GoriaSharded[K, V]) RemoveWithKeyOnly(key K) (bool, error) {
+	return c.shard(key).RemoveWithKeyOnly(key)
+}
+
+func (c *GoriaSharded[K, V]) Remove(key K, oldValue V) (bool, error) {
+	return c.shard(key).Remove(key, oldValue)
+}
+
+// RemoveAll removes the entries segment by segment, the first error is returned and the following segments left untouched.
+func (c *GoriaSharded[K, V]) RemoveAll(m map[K]V) error {
+	for i, entries := range c.partitionMap(m) {
+		if len(entries) == 0 {
+			continue
+		}
+		if err := c.shards[i].RemoveAll(entries); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *GoriaSharded[K, V]) RemoveAllWithoutParameters() error {
+	for _, shard := range c.shards {
+		if err := shard.RemoveAllWithoutParameters(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *GoriaSharded[K, V]) GetAndRemove(key K) (V, bool, error) {
+	return c.shard(key).GetAndRemove(key)
+}
+
+// Clear empties every segment without invoking the eviction callback.
+func (c *GoriaSharded[K, V]) Clear() {
+	for _, shard := range c.shards {
+		shard.Clear()
+	}
+}
+
+// Invoke runs processor on the entry of key while holding the lock of its segment.
+func (c *GoriaSharded[K, V]) Invoke(key K, processor goria.EntryProcessor[K, V], args ...interface{}) (interface{}, error) {
+	return c.shard(key).Invoke(key, processor, args...)
+}
+
+// InvokeAll runs processor on the entry of every key, every segment being locked in turn.
+func (c *GoriaSharded[K, V]) InvokeAll(keys []K, processor goria.EntryProcessor[K, V], args ...interface{}) map[K]goria.EntryProcessorResult {
+	results := make(map[K]goria.EntryProcessorResult)
+	for i, shardKeys := range c.partition(keys) {
+		if len(shardKeys) == 0 {
+			continue
+		}
+		for k, result := range c.shards[i].InvokeAll(shardKeys, processor, args...) {
+			results[k] = result
+		}
+	}
+	return results
+}
+
+// RegisterCacheEntryListener registers listener on every segment, the returned id deregisters it from all of them.
+func (c *GoriaSharded[K, V]) RegisterCacheEntryListener(listener goria.CacheEntryListener[K, V], filter goria.CacheEntryEventFilter[K, V]) uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ids := make([]uint64, len(c.shards))
+	for i, shard := range c.shards {
+		ids[i] = shard.RegisterCacheEntryListener(listener, filter)
+	}
+	c.listenerID++
+	c.listeners[c.listenerID] = ids
+	return c.listenerID
+}
+
+func (c *GoriaSharded[K, V]) DeregisterCacheEntryListener(id uint64) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ids, exists := c.listeners[id]
+	if !exists {
+		return false
+	}
+	for i, shard := range c.shards {
+		shard.DeregisterCacheEntryListener(ids[i])
+	}
+	delete(c.listeners, id)
+	return true
+}
+
+// Keys returns the keys of every segment, segment after segment.
+func (c *GoriaSharded[K, V]) Keys() []K {
+	var keys []K
+	for _, shard := range c.shards {
+		keys = append(keys, shard.Keys()...)
+	}
+	return keys
+}
+
+func (c *GoriaSharded[K, V]) ContainsKey(key K) bool {
+	return c.shard(key).ContainsKey(key)
+}
+
+func (c *GoriaSharded[K, V]) Len() int {
+	length := 0
+	for _, shard := range c.shards {
+		length += shard.Len()
+	}
+	return length
+}
+
+func (c *GoriaSharded[K, V]) GetName() string {
+	return c.Name
+}
+
+func (c *GoriaSharded[K, V]) IsStatsEnabled() bool {
+	return c.statsEnabled.Load()
+}
+
+// SetStatisticsEnabled switches the collection of statistics on or off in every segment.
+func (c *GoriaSharded[K, V]) SetStatisticsEnabled(enabled bool) {
+	c.statsEnabled.Store(enabled)
+	for _, shard := range c.shards {
+		shard.SetStatisticsEnabled(enabled)
+	}
+}
+
+// ClearStatistics resets the counters of every segment.
+func (c *GoriaSharded[K, V]) ClearStatistics() {
+	for _, shard := range c.shards {
+		shard.ClearStatistics()
+	}
+}
+
+// GetStats returns the sum of the statistics of the segments, every segment being read in turn, so that
+// the snapshot of every segment is consistent but the segments are not read at the same point in time.
+func (c *GoriaSharded[K, V]) GetStats() goria.CacheStats {
+	var stats goria.CacheStats
+	for _, shard := range c.shards {
+		s := shard.GetStats()
+		stats.Items += s.Items
+		stats.Gets += s.Gets
+		stats.Hits += s.Hits
+		stats.Evictions += s.Evictions
+		stats.Miss += s.Miss
+		stats.Loads += s.Loads
+		stats.LoadErrors += s.LoadErrors
+		stats.AdmissionRejections += s.AdmissionRejections
+		stats.Weight += s.Weight
+		stats.Puts += s.Puts
+		stats.Removals += s.Removals
+		stats.Expirations += s.Expirations
+		stats.GetTime += s.GetTime
+		stats.PutTime += s.PutTime
+		stats.RemoveTime += s.RemoveTime
+		stats.GetLatency = stats.GetLatency.Add(s.GetLatency)
+		stats.PutLatency = stats.PutLatency.Add(s.PutLatency)
+		stats.RemoveLatency = stats.RemoveLatency.Add(s.RemoveLatency)
+	}
+	stats.Capacity = int64(c.size)
+	return stats
+}
+
+// ShardStats returns the statistics of every segment, in segment order.
+func (c *GoriaSharded[K, V]) ShardStats() []goria.CacheStats {
+	stats := make([]goria.CacheStats, len(c.shards))
+	for i, shard := range c.shards {
+		stats[i] = shard.GetStats()
+	}
+	return stats
+}
+
+// GetSize returns the maximum number of entries of the cache, the sum of the sizes of its segments may be larger.
+func (c *GoriaSharded[K, V]) GetSize() int {
+	return c.size
+}
+
+// Shards returns the number of segments.
+func (c *GoriaSharded[K, V]) Shards() int {
+	return len(c.shards)
+}
+
+func (c *GoriaSharded[K, V]) index(key K) int {
+	return int(keyhash.Sum(c.seed, key) % uint64(len(c.shards)))
+}
+
+func (c *GoriaSharded[K, V]) shard(key K) goria.Cache[K, V] {
+	return c.shards[c.index(key)]
+}
+
+func (c *GoriaSharded[K, V]) partition(keys []K) [][]K {
+	partitions := make([][]K, len(c.shards))
+	for _, key := range keys {
+		i := c.index(key)
+		partitions[i] = append(partitions[i], key)
+	}
+	return partitions
+}
+
+func (c *GoriaSharded[K, V]) partitionMap(m map[K]V) []map[K]V {
+	partitions := make([]map[K]V, len(c.shards))
+	for key, value := range m {
+		i := c.index(key)
+		if partitions[i] == nil {
+			partitions[i] = make(map[K]V)
+		}
+		partitions[i][key] = value
+	}
+	return partitions
+}
+
+// namedRecorder records the operations of a segment under the name of the sharded cache.
+type namedRecorder struct {
+	recorder goria.MetricsRecorder
+	name     string
+}
+
+func (r namedRecorder) RecordHit(cache string) {
+	r.recorder.RecordHit(r.name)
+}
+
+func (r namedRecorder) RecordMiss(cache string) {
+	r.recorder.RecordMiss(r.name)
+}
+
+func (r namedRecorder) RecordEviction(cache string, reason goria.EventType) {
+	r.recorder.RecordEviction(r.name, reason)
+}
+
+func (r namedRecorder) RecordLoad(cache string, d time.Duration, err error) {
+	r.recorder.RecordLoad(r.name, d, err)
+}
@@ -0,0 +1,406 @@
+package goriasharded
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/gorialru"
+	"github.com/oscerd/goria/goriamru"
+)
+
+func TestGoria(t *testing.T) {
+
+	_, err := New[int, int]("sample", 0, goria.Configuration[int, int]{Size: 128, Factory: gorialru.Factory[int, int]})
+	if err == nil {
+		t.Fatalf("cache should be rejected with zero shards")
+	}
+
+	_, err = New[int, int]("sample", 4, goria.Configuration[int, int]{Size: 128})
+	if err != goria.ErrNoFactory {
+		t.Fatalf("cache should be rejected without a factory, got %v", err)
+	}
+
+	l, err := New[int, int]("sample", 4, goria.Configuration[int, int]{Size: 128, StatsEnabled: true, Factory: gorialru.Factory[int, int]})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if l.GetName() != "sample" || l.Shards() != 4 {
+		t.Fatalf("Wrong name %v or shards %v", l.GetName(), l.Shards())
+	}
+
+	for i := 0; i < 256; i++ {
+		l.Put(i, i)
+	}
+
+	if l.Len() > 128 || l.Len() != len(l.Keys()) {
+		t.Fatalf("Wrong len %v with %v keys", l.Len(), len(l.Keys()))
+	}
+
+	for _, k := range l.Keys() {
+		if v, ok := l.Get(k); !ok || v != k {
+			t.Fatalf("wrong key: %v", k)
+		}
+	}
+
+	if v, ok := l.Get(255); !ok || v != 255 {
+		t.Fatalf("the last key should not have been evicted")
+	}
+
+	var items, evictions, gets int64
+	for i, s := range l.ShardStats() {
+		if s.Items > 32 {
+			t.Fatalf("Wrong Items stat %v of shard %v", s.Items, i)
+		}
+		items += s.Items
+		evictions += s.Evictions
+		gets += s.Gets
+	}
+
+	stats := l.GetStats()
+	if stats.Items != items || stats.Evictions != evictions || stats.Gets != gets {
+		t.Fatalf("Wrong aggregated stats %v", stats)
+	}
+
+	if stats.Items != int64(l.Len()) || stats.Items+stats.Evictions != 256 {
+		t.Fatalf("Wrong Items %v or Evictions %v stat", stats.Items, stats.Evictions)
+	}
+
+	l.Clear()
+
+	l.PutAll(map[int]int{1: 1, 2: 2, 3: 3, 4: 4})
+
+	values := l.GetAll([]int{1, 2, 3, 4, 5})
+	if len(values) != 4 || values[3] != 3 {
+		t.Fatalf("Wrong values %v", values)
+	}
+
+	l.RemoveAll(map[int]int{1: 1, 2: 0})
+
+	if l.ContainsKey(1) || !l.ContainsKey(2) {
+		t.Fatalf("only key 1 should have been removed")
+	}
+
+	results := l.InvokeAll([]int{2, 3, 5}, func(entry goria.MutableEntry[int, int], args ...interface{}) (interface{}, error) {
+		if !entry.Exists() {
+			return nil, nil
+		}
+		entry.SetValue(entry.GetValue() * 10)
+		return entry.GetValue(), nil
+	})
+
+	if len(results) != 2 || results[2].Value != 20 || results[3].Value != 30 {
+		t.Fatalf("Wrong results %v", results)
+	}
+
+	var events []goria.CacheEntryEvent[int, int]
+	id := l.RegisterCacheEntryListener(func(event goria.CacheEntryEvent[int, int]) {
+		events = append(events, event)
+	}, nil)
+
+	l.Put(6, 6)
+	l.RemoveWithKeyOnly(6)
+
+	if len(events) != 2 || events[0].Type != goria.Created || events[1].Type != goria.Removed {
+		t.Fatalf("Wrong events %v", events)
+	}
+
+	if !l.DeregisterCacheEntryListener(id) || l.DeregisterCacheEntryListener(id) {
+		t.Fatalf("listener %v should be deregistered once", id)
+	}
+
+	l.Put(7, 7)
+
+	if len(events) != 2 {
+		t.Fatalf("no event should be received after deregistration, got %v", events)
+	}
+
+	if err := l.RemoveAllWithoutParameters(); err != nil || l.Len() != 0 {
+		t.Fatalf("cache should be empty, got len %v and err %v", l.Len(), err)
+	}
+}
+
+type testLoader struct{}
+
+var errLoad = errors.New("load failed")
+
+func (testLoader) Load(key int) (int, bool, error) {
+	if key < 0 {
+		return 0, false, errLoad
+	}
+	return key * 2, true, nil
+}
+
+func (l testLoader) LoadAll(keys []int) (map[int]int, error) {
+	values := make(map[int]int)
+	for _, key := range keys {
+		value, _, err := l.Load(key)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+func TestGoriaLoader(t *testing.T) {
+
+	l, err := New[int, int]("sample", 4, goria.Configuration[int, int]{
+		Size:        128,
+		CacheLoader: testLoader{},
+		ReadThrough: true,
+		Factory:     goriamru.Factory[int, int],
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if v, ok := l.Get(3); !ok || v != 6 {
+		t.Fatalf("key 3 should be read through, got %v", v)
+	}
+
+	values, err := l.GetAllOrLoad([]int{3, -1})
+	if err != errLoad || values[3] != 6 {
+		t.Fatalf("Wrong values %v or err %v", values, err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var loadErr error
+	l.LoadAll([]int{10, 11, 12, 13, 14, 15, 16, 17}, false, func(err error) {
+		loadErr = err
+		wg.Done()
+	})
+	wg.Wait()
+
+	if loadErr != nil {
+		t.Fatalf("err: %v", loadErr)
+	}
+
+	for k := 10; k < 18; k++ {
+		if !l.ContainsKey(k) {
+			t.Fatalf("key %v should have been loaded", k)
+		}
+	}
+
+	wg.Add(1)
+	l.LoadAll([]int{20, -20}, false, func(err error) {
+		loadErr = err
+		wg.Done()
+	})
+	wg.Wait()
+
+	if loadErr != errLoad {
+		t.Fatalf("the load error should be reported, got %v", loadErr)
+	}
+}
+
+func TestGoriaManager(t *testing.T) {
+
+	m := goria.NewCacheManager()
+
+	c, err := goria.CreateCache(m, "sharded", goria.Configuration[string, int]{
+		Size:    64,
+		Factory: Factory(8, gorialru.Factory[string, int]),
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	sharded, ok := c.(*GoriaSharded[string, int])
+	if !ok || sharded.Shards() != 8 || sharded.GetSize() != 64 {
+		t.Fatalf("Wrong cache %v", c)
+	}
+
+	c.Put("a", 1)
+
+	got, err := goria.GetCache[string, int](m, "sharded")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if v, ok := got.Get("a"); !ok || v != 1 {
+		t.Fatalf("Wrong value %v", v)
+	}
+}
+
+func TestGoriaConcurrent(t *testing.T) {
+
+	l, err := New[int, int]("sample", 16, goria.Configuration[int, int]{Size: 1024, StatsEnabled: true, Factory: gorialru.Factory[int, int]})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				k := g*1000 + i
+				l.Put(k, k)
+				l.Get(k)
+				l.ContainsKey(i)
+				if i%100 == 0 {
+					l.Keys()
+					l.GetStats()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	stats := l.GetStats()
+	if stats.Gets != 8000 || stats.Items != int64(l.Len()) || l.Len() > 1024 {
+		t.Fatalf("Wrong stats %v with len %v", stats, l.Len())
+	}
+}
+
+const benchmarkSize = 8192
+
+func TestGoriaStatisticsControl(t *testing.T) {
+
+	l, err := New[int, int]("sample", 4, goria.Configuration[int, int]{Size: 128, Factory: gorialru.Factory[int, int]})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.SetStatisticsEnabled(true)
+	for i := 0; i < 64; i++ {
+		l.Put(i, i)
+		l.Get(i)
+	}
+
+	if !l.IsStatsEnabled() || l.GetStats().Gets != 64 || l.GetStats().Puts != 64 {
+		t.Fatalf("Wrong stats %+v", l.GetStats())
+	}
+
+	l.ClearStatistics()
+
+	for _, stats := range l.ShardStats() {
+		if stats.Gets != 0 || stats.Puts != 0 {
+			t.Fatalf("every segment should be cleared, got %+v", stats)
+		}
+	}
+
+	l.SetStatisticsEnabled(false)
+	l.Get(1)
+
+	if l.IsStatsEnabled() || l.GetStats().Gets != 0 || l.GetStats().Items != 64 || l.GetStats().Capacity != 128 {
+		t.Fatalf("statistics shouldn't be collected but Items and Capacity reported, got %+v", l.GetStats())
+	}
+}
+
+func benchmarkCaches(b *testing.B, run func(b *testing.B, c goria.Cache[int, int])) {
+	single, err := gorialru.New[int, int]("single", benchmarkSize, nil, false)
+	if err != nil {
+		b.Fatalf("err: %v", err)
+	}
+	b.Run("lru", func(b *testing.B) { run(b, single) })
+
+	for _, shards := range []int{4, 16, 64} {
+		sharded, err := New[int, int]("sharded", shards, goria.Configuration[int, int]{Size: benchmarkSize, Factory: gorialru.Factory[int, int]})
+		if err != nil {
+			b.Fatalf("err: %v", err)
+		}
+		b.Run("sharded-"+strconv.Itoa(shards), func(b *testing.B) { run(b, sharded) })
+	}
+}
+
+// start spreads the goroutines of a parallel benchmark over the key space, so that they do not walk the keys in lockstep.
+func start(goroutines *atomic.Int64) int {
+	return int(goroutines.Add(1)) * 7919
+}
+
+func BenchmarkGoriaParallelGet(b *testing.B) {
+	benchmarkCaches(b, func(b *testing.B, c goria.Cache[int, int]) {
+		for i := 0; i < benchmarkSize; i++ {
+			c.Put(i, i)
+		}
+		b.ResetTimer()
+		var goroutines atomic.Int64
+		b.RunParallel(func(pb *testing.PB) {
+			i := start(&goroutines)
+			for pb.Next() {
+				c.Get(i % benchmarkSize)
+				i++
+			}
+		})
+	})
+}
+
+func BenchmarkGoriaParallelPut(b *testing.B) {
+	benchmarkCaches(b, func(b *testing.B, c goria.Cache[int, int]) {
+		var goroutines atomic.Int64
+		b.RunParallel(func(pb *testing.PB) {
+			i := start(&goroutines)
+			for pb.Next() {
+				c.Put(i%(2*benchmarkSize), i)
+				i++
+			}
+		})
+	})
+}
+
+func BenchmarkGoriaParallelMixed(b *testing.B) {
+	benchmarkCaches(b, func(b *testing.B, c goria.Cache[int, int]) {
+		var goroutines atomic.Int64
+		b.RunParallel(func(pb *testing.PB) {
+			i := start(&goroutines)
+			for pb.Next() {
+				if i%4 == 0 {
+					c.Put(i%(2*benchmarkSize), i)
+				} else {
+					c.Get(i % (2 * benchmarkSize))
+				}
+				i++
+			}
+		})
+	})
+}
+
+// hitRecorder records the hits only, by cache name.
+type hitRecorder struct {
+	goria.NoopMetricsRecorder
+	lock sync.Mutex
+	hits map[string]int
+}
+
+func (r *hitRecorder) RecordHit(cache string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.hits[cache]++
+}
+
+func TestGoriaMetricsRecorder(t *testing.T) {
+
+	recorder := &hitRecorder{hits: make(map[string]int)}
+	l, err := New[int, int]("sample", 4, goria.Configuration[int, int]{Size: 128, MetricsRecorder: recorder, Factory: gorialru.Factory[int, int]})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i := 0; i < 64; i++ {
+		l.Put(i, i)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 64; i++ {
+		wg.Add(1)
+		go func(k int) {
+			defer wg.Done()
+			l.Get(k)
+			l.Get(-k - 1)
+		}(i)
+	}
+	wg.Wait()
+
+	if len(recorder.hits) != 1 || recorder.hits["sample"] != 64 {
+		t.Fatalf("the segments should record their hits under the name of the cache, got %v", recorder.hits)
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Package goriasieve provides the functionality of a SIEVE Cache with an eye to JSR 107
+
+The SIEVE policy keeps the entries in a queue, newest first, swept by a hand
+from the oldest entry towards the newest one. A hit only sets the visited bit
+of the entry, when an entry has to be evicted the hand clears the visited bits
+it meets and evicts the first entry that was not visited, starting again from
+the oldest entry once it reaches the newest one. Unlike CLOCK the entries that
+survive a sweep keep their place in the queue, so that new entries, which are
+the most likely to be used once, are evicted sooner.
+*/
+package goriasieve
+
+import (
+	"container/list"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
+
+type GoriaSieve[K comparable, V any] struct {
+	*engine.Cache[K, V]
+}
+
+var _ goria.Cache[string, interface{}] = (*GoriaSieve[string, interface{}])(nil)
+
+func New[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*GoriaSieve[K, V], error) {
+	return NewWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
+}
+
+func NewWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*GoriaSieve[K, V], error) {
+	c, err := engine.New(name, config, newPolicy[K]())
+	if err != nil {
+		return nil, err
+	}
+	return &GoriaSieve[K, V]{c}, nil
+}
+
+// Factory is the goria.CacheFactory of the SIEVE policy, to be used with a goria.CacheManager.
+func Factory[K comparable, V any](name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+	c, err := NewWithConfiguration(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type slot[K comparable] struct {
+	key     K
+	visited bool
+}
+
+// policy keeps the keys in a queue, the newest at the front, the hand moving from the back to the front.
+type policy[K comparable] struct {
+	queue    *list.List
+	hand     *list.Element
+	elements map[K]*list.Element
+}
+
+func newPolicy[K comparable]() *policy[K] {
+	return &policy[K]{
+		queue:    list.New(),
+		elements: make(map[K]*list.Element),
+	}
+}
+
+func (p *policy[K]) OnAccess(key K) {
+	p.elements[key].Value.(*slot[K]).visited = true
+}
+
+func (p *policy[K]) OnInsert(key K) {
+	p.elements[key] = p.queue.PushFront(&slot[K]{key: key})
+}
+
+func (p *policy[K]) OnRemove(key K) {
+	element := p.elements[key]
+	if element == p.hand {
+		p.hand = element.Prev()
+	}
+	p.queue.Remove(element)
+	delete(p.elements, key)
+}
+
+// Victim sweeps the queue from the hand towards the newest key, clearing the visited bits, up to a key that was not visited.
+// The key being inserted, at the head of the queue, is left out.
+func (p *policy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	if p.queue.Len() == 0 || (p.queue.Len() == 1 && !evictable(p.queue.Front().Value.(*slot[K]).key)) {
+		return
+	}
+	for {
+		if p.hand == nil {
+			p.hand = p.queue.Back()
+		}
+		s := p.hand.Value.(*slot[K])
+		if !s.visited && evictable(s.key) {
+			return s.key, true
+		}
+		s.visited = false
+		p.hand = p.hand.Prev()
+	}
+}
+
+// Keys returns the keys oldest first.
+func (p *policy[K]) Keys() []K {
+	keys := make([]K, 0, p.queue.Len())
+	for element := p.queue.Back(); element != nil; element = element.Prev() {
+		keys = append(keys, element.Value.(*slot[K]).key)
+	}
+	return keys
+}
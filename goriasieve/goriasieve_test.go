@@ -0,0 +1,92 @@
+package goriasieve
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestGoriaSieve(t *testing.T) {
+
+	l, err := New[string, int]("sample", 3, nil, false)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Put("a", 1)
+	l.Put("b", 2)
+	l.Put("c", 3)
+
+	// a survives the sweep keeping its place as the oldest key, b is evicted
+	l.Get("a")
+	l.Put("d", 4)
+
+	if l.ContainsKey("b") || !l.ContainsKey("a") {
+		t.Fatalf("the oldest key not visited should be evicted, got %v", l.Keys())
+	}
+
+	keys := l.Keys()
+	expected := []string{"a", "c", "d"}
+	for i, k := range expected {
+		if keys[i] != k {
+			t.Fatalf("Wrong keys %v, expected %v", keys, expected)
+		}
+	}
+
+	// the hand goes on from where b was, towards the newest keys, so c is evicted before a is considered again
+	l.Get("a")
+	l.Put("e", 5)
+
+	if l.ContainsKey("c") || !l.ContainsKey("a") || !l.ContainsKey("d") {
+		t.Fatalf("c should be evicted, got %v", l.Keys())
+	}
+}
+
+func TestGoriaScan(t *testing.T) {
+
+	l, err := New[int, int]("sample", 100, nil, true)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// every round uses 50 hot keys twice then scans 100 keys used once
+	for round := 0; round < 20; round++ {
+		for repeat := 0; repeat < 2; repeat++ {
+			for i := 0; i < 50; i++ {
+				if _, ok := l.Get(i); !ok {
+					l.Put(i, i)
+				}
+			}
+		}
+		for i := 0; i < 100; i++ {
+			l.Put(1000+round*100+i, i)
+		}
+	}
+
+	for i := 0; i < 50; i++ {
+		if !l.ContainsKey(i) {
+			t.Fatalf("hot key %v should survive the scans", i)
+		}
+	}
+}
+
+func BenchmarkGoriaGet(b *testing.B) {
+	l, err := New[string, int]("sample", 8192, nil, false)
+	if err != nil {
+		b.Fatalf("err: %v", err)
+	}
+	keys := make([]string, 8192)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+		l.Put(keys[i], i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			l.Get(keys[i%len(keys)])
+			i++
+		}
+	})
+}
@@ -0,0 +1,153 @@
+/*
+Package goriaslru provides the functionality of a Segmented LRU Cache with an eye to JSR 107
+
+New entries enter a probation segment and are promoted to a protected segment
+when they are used again, the least recently used protected entry being demoted
+to probation when the protected segment is full. Entries are evicted from the
+probation segment first, so that a scan of keys used once cannot evict the
+entries used more than once.
+*/
+package goriaslru
+
+import (
+	"container/list"
+	"errors"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
+
+// DefaultProtectedRatio is the share of the cache given to the protected segment by New and NewWithConfiguration.
+const DefaultProtectedRatio = 0.8
+
+var ErrProtectedRatio = errors.New("The Goria Cache need a protected ratio between 0 and 1")
+
+type GoriaSLRU[K comparable, V any] struct {
+	*engine.Cache[K, V]
+}
+
+var _ goria.Cache[string, interface{}] = (*GoriaSLRU[string, interface{}])(nil)
+
+func New[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*GoriaSLRU[K, V], error) {
+	return NewWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
+}
+
+func NewWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*GoriaSLRU[K, V], error) {
+	return NewWithProtectedRatio(name, config, DefaultProtectedRatio)
+}
+
+// NewWithProtectedRatio builds a cache whose protected segment holds up to protectedRatio of its entries.
+func NewWithProtectedRatio[K comparable, V any](name string, config goria.Configuration[K, V], protectedRatio float64) (*GoriaSLRU[K, V], error) {
+	if protectedRatio <= 0 || protectedRatio >= 1 {
+		return nil, ErrProtectedRatio
+	}
+	c, err := engine.New(name, config, newPolicy[K](int(float64(config.Size)*protectedRatio)))
+	if err != nil {
+		return nil, err
+	}
+	return &GoriaSLRU[K, V]{c}, nil
+}
+
+// Factory is the goria.CacheFactory of the SLRU policy, to be used with a goria.CacheManager.
+func Factory[K comparable, V any](name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+	c, err := NewWithConfiguration(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// FactoryWithProtectedRatio returns the goria.CacheFactory of the SLRU policy with the given protected ratio.
+func FactoryWithProtectedRatio[K comparable, V any](protectedRatio float64) goria.CacheFactory[K, V] {
+	return func(name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+		c, err := NewWithProtectedRatio(name, config, protectedRatio)
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+type node struct {
+	list    *list.List
+	element *list.Element
+}
+
+// policy holds the probation and protected segments, most recently used at the front,
+// the protected one holding up to protectedSize keys.
+type policy[K comparable] struct {
+	protectedSize int
+	probation     *list.List
+	protected     *list.List
+	nodes         map[K]*node
+}
+
+func newPolicy[K comparable](protectedSize int) *policy[K] {
+	return &policy[K]{
+		protectedSize: protectedSize,
+		probation:     list.New(),
+		protected:     list.New(),
+		nodes:         make(map[K]*node),
+	}
+}
+
+// OnAccess promotes a probation key to the protected segment, demoting the least recently used protected key when it is full.
+func (p *policy[K]) OnAccess(key K) {
+	n := p.nodes[key]
+	if n.list == p.protected {
+		p.protected.MoveToFront(n.element)
+		return
+	}
+	if p.protectedSize == 0 {
+		p.probation.MoveToFront(n.element)
+		return
+	}
+	if p.protected.Len() >= p.protectedSize {
+		demoted := p.protected.Back()
+		p.move(p.nodes[demoted.Value.(K)], p.probation)
+	}
+	p.move(n, p.protected)
+}
+
+func (p *policy[K]) OnInsert(key K) {
+	p.nodes[key] = &node{p.probation, p.probation.PushFront(key)}
+}
+
+func (p *policy[K]) OnRemove(key K) {
+	n := p.nodes[key]
+	n.list.Remove(n.element)
+	delete(p.nodes, key)
+}
+
+// Victim returns the least recently used evictable probation key, the least recently used evictable
+// protected key when probation holds none.
+func (p *policy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	for _, l := range []*list.List{p.probation, p.protected} {
+		for element := l.Back(); element != nil; element = element.Prev() {
+			if key := element.Value.(K); evictable(key) {
+				return key, true
+			}
+		}
+	}
+	return
+}
+
+// Keys returns the probation keys then the protected keys, least recently used first.
+func (p *policy[K]) Keys() []K {
+	keys := make([]K, 0, len(p.nodes))
+	for _, l := range []*list.List{p.probation, p.protected} {
+		for element := l.Back(); element != nil; element = element.Prev() {
+			keys = append(keys, element.Value.(K))
+		}
+	}
+	return keys
+}
+
+func (p *policy[K]) move(n *node, l *list.List) {
+	key := n.list.Remove(n.element)
+	n.list, n.element = l, l.PushFront(key)
+}
@@ -0,0 +1,81 @@
+package goriaslru
+
+import (
+	"testing"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/gorialru"
+)
+
+func TestGoriaProtectedRatio(t *testing.T) {
+
+	for _, ratio := range []float64{0, 1, -0.5, 2} {
+		if _, err := NewWithProtectedRatio("sample", goria.Configuration[int, int]{Size: 10}, ratio); err != ErrProtectedRatio {
+			t.Fatalf("cache should be rejected with a protected ratio of %v, got %v", ratio, err)
+		}
+	}
+}
+
+func TestGoriaSegments(t *testing.T) {
+
+	l, err := NewWithProtectedRatio("sample", goria.Configuration[string, int]{Size: 4}, 0.5)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i, k := range []string{"a", "b", "c", "d"} {
+		l.Put(k, i)
+	}
+
+	// a, b and c are promoted in turn, a being demoted when c enters the protected segment of 2 keys
+	l.Get("a")
+	l.Get("b")
+	l.Get("c")
+
+	keys := l.Keys()
+	expected := []string{"d", "a", "b", "c"}
+	for i, k := range expected {
+		if keys[i] != k {
+			t.Fatalf("Wrong keys %v, expected %v", keys, expected)
+		}
+	}
+
+	l.Put("e", 4)
+
+	if l.ContainsKey("d") {
+		t.Fatalf("the least recently used probation key should be evicted, got %v", l.Keys())
+	}
+}
+
+func TestGoriaScan(t *testing.T) {
+
+	l, err := New[int, int]("slru", 100, nil, false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	lru, err := gorialru.New[int, int]("lru", 100, nil, false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for _, c := range []goria.Cache[int, int]{l, lru} {
+		for i := 0; i < 40; i++ {
+			c.Put(i, i)
+			c.Get(i)
+		}
+		for i := 1000; i < 2000; i++ {
+			c.Put(i, i)
+		}
+	}
+
+	for i := 0; i < 40; i++ {
+		if !l.ContainsKey(i) {
+			t.Fatalf("hot key %v should survive a scan", i)
+		}
+		if lru.ContainsKey(i) {
+			t.Fatalf("hot key %v should be flushed out of the LRU by a scan", i)
+		}
+	}
+}
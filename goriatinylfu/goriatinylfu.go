@@ -0,0 +1,173 @@
+/*
+Package goriatinylfu provides the functionality of a W-TinyLFU Cache with an eye to JSR 107
+
+New entries enter a small LRU window, holding one percent of the cache, before
+moving to a main segmented LRU made of a probation and a protected segment.
+When the main region is full the entry leaving the window is admitted only if
+it has been used more often lately than the probation entry it would evict,
+frequencies being estimated by a count-min sketch aged periodically behind a
+doorkeeper bloom filter. The refused entries are evicted and counted as
+AdmissionRejections in the statistics.
+*/
+package goriatinylfu
+
+import (
+	"container/list"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/internal/engine"
+)
+
+const (
+	windowRatio    = 0.01
+	protectedRatio = 0.8
+)
+
+type GoriaTinyLFU[K comparable, V any] struct {
+	*engine.Cache[K, V]
+}
+
+var _ goria.Cache[string, interface{}] = (*GoriaTinyLFU[string, interface{}])(nil)
+
+func New[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*GoriaTinyLFU[K, V], error) {
+	return NewWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
+}
+
+func NewWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*GoriaTinyLFU[K, V], error) {
+	c, err := engine.New(name, config, newPolicy[K](config.Size))
+	if err != nil {
+		return nil, err
+	}
+	return &GoriaTinyLFU[K, V]{c}, nil
+}
+
+// Factory is the goria.CacheFactory of the W-TinyLFU policy, to be used with a goria.CacheManager.
+func Factory[K comparable, V any](name string, config goria.Configuration[K, V]) (goria.Cache[K, V], error) {
+	c, err := NewWithConfiguration(name, config)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+type node struct {
+	list    *list.List
+	element *list.Element
+}
+
+// policy holds the window and the probation and protected segments of the main region, most recently used at the front.
+// rejected tells whether the last victim is the candidate leaving the window.
+type policy[K comparable] struct {
+	windowSize    int
+	mainSize      int
+	protectedSize int
+	window        *list.List
+	probation     *list.List
+	protected     *list.List
+	nodes         map[K]*node
+	sketch        *sketch[K]
+	rejected      bool
+}
+
+func newPolicy[K comparable](size int) *policy[K] {
+	windowSize := max(int(float64(size)*windowRatio), 1)
+	mainSize := max(size-windowSize, 0)
+	return &policy[K]{
+		windowSize:    windowSize,
+		mainSize:      mainSize,
+		protectedSize: int(float64(mainSize) * protectedRatio),
+		window:        list.New(),
+		probation:     list.New(),
+		protected:     list.New(),
+		nodes:         make(map[K]*node),
+		sketch:        newSketch[K](size),
+	}
+}
+
+func (p *policy[K]) OnAccess(key K) {
+	p.sketch.increment(key)
+	n := p.nodes[key]
+	switch n.list {
+	case p.window, p.protected:
+		n.list.MoveToFront(n.element)
+	case p.probation:
+		if p.protectedSize == 0 {
+			p.probation.MoveToFront(n.element)
+			return
+		}
+		if p.protected.Len() >= p.protectedSize {
+			p.move(p.nodes[p.protected.Back().Value.(K)], p.probation)
+		}
+		p.move(n, p.protected)
+	}
+}
+
+func (p *policy[K]) OnInsert(key K) {
+	p.sketch.increment(key)
+	p.nodes[key] = &node{p.window, p.window.PushFront(key)}
+}
+
+func (p *policy[K]) OnRemove(key K) {
+	n := p.nodes[key]
+	n.list.Remove(n.element)
+	delete(p.nodes, key)
+}
+
+// Victim moves the least recently used window key to probation when the window exceeds its size,
+// the candidate being then returned when it is not used more often than the least recently used probation key,
+// or when that key may not be evicted.
+func (p *policy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	p.rejected = false
+	if p.window.Len() > p.windowSize {
+		candidate := p.window.Back().Value.(K)
+		p.move(p.nodes[candidate], p.probation)
+		if p.probation.Len()+p.protected.Len() <= p.mainSize {
+			return p.Victim(evictable)
+		}
+		victim := p.probation.Back().Value.(K)
+		if victim == candidate {
+			if p.protected.Len() == 0 {
+				p.rejected = true
+				return candidate, true
+			}
+			victim = p.protected.Back().Value.(K)
+		}
+		if evictable(victim) && p.sketch.estimate(candidate) > p.sketch.estimate(victim) {
+			return victim, true
+		}
+		p.rejected = true
+		return candidate, true
+	}
+	for _, l := range []*list.List{p.probation, p.protected, p.window} {
+		for element := l.Back(); element != nil; element = element.Prev() {
+			if key := element.Value.(K); evictable(key) {
+				return key, true
+			}
+		}
+	}
+	return
+}
+
+func (p *policy[K]) Rejected() bool {
+	return p.rejected
+}
+
+// Keys returns the probation, protected and window keys, least recently used first.
+func (p *policy[K]) Keys() []K {
+	keys := make([]K, 0, len(p.nodes))
+	for _, l := range []*list.List{p.probation, p.protected, p.window} {
+		for element := l.Back(); element != nil; element = element.Prev() {
+			keys = append(keys, element.Value.(K))
+		}
+	}
+	return keys
+}
+
+func (p *policy[K]) move(n *node, l *list.List) {
+	key := n.list.Remove(n.element)
+	n.list, n.element = l, l.PushFront(key)
+}
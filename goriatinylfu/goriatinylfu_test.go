@@ -0,0 +1,153 @@
+package goriatinylfu
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/gorialru"
+)
+
+func TestGoriaRejections(t *testing.T) {
+
+	l, err := New[int, int]("sample", 128, nil, true)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i := 0; i < 256; i++ {
+		l.Put(i, i)
+	}
+
+	if l.GetStats().AdmissionRejections == 0 || l.GetStats().AdmissionRejections > l.GetStats().Evictions {
+		t.Fatalf("Wrong AdmissionRejections stat %v", l.GetStats().AdmissionRejections)
+	}
+
+	if !l.ContainsKey(255) {
+		t.Fatalf("the last key put should be in the window")
+	}
+}
+
+func TestSketch(t *testing.T) {
+
+	s := newSketch[int](100)
+
+	s.increment(1)
+
+	if s.estimate(1) != 1 {
+		t.Fatalf("a key used once should only be in the doorkeeper, got %v", s.estimate(1))
+	}
+
+	for i := 0; i < 9; i++ {
+		s.increment(1)
+	}
+
+	if s.estimate(1) < 10 {
+		t.Fatalf("Wrong estimate %v", s.estimate(1))
+	}
+
+	for i := 0; i < 30; i++ {
+		s.increment(2)
+	}
+
+	if s.estimate(2) != maxCount+1 {
+		t.Fatalf("the counters should saturate, got %v", s.estimate(2))
+	}
+
+	s.reset()
+
+	if s.estimate(2) != maxCount/2 || s.estimate(1) < 4 || s.estimate(1) > 5 {
+		t.Fatalf("the counters should be halved and the doorkeeper emptied, got %v and %v", s.estimate(2), s.estimate(1))
+	}
+
+	for i := 0; i < s.sampleSize; i++ {
+		s.increment(3)
+	}
+
+	if s.estimate(2) >= maxCount/2 {
+		t.Fatalf("the counters should be aged after %v additions, got %v", s.sampleSize, s.estimate(2))
+	}
+}
+
+func TestGoriaAdmission(t *testing.T) {
+
+	l, err := New[int, int]("sample", 100, nil, true)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	lru, err := gorialru.New[int, int]("lru", 100, nil, true)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// every round uses 50 hot keys then scans 100 keys used once, which flushes the hot keys out of an LRU
+	scan := 1000
+	for round := 0; round < 100; round++ {
+		for i := 0; i < 50; i++ {
+			for _, c := range []goria.Cache[int, int]{l, lru} {
+				if _, ok := c.Get(i); !ok {
+					c.Put(i, i)
+				}
+			}
+		}
+		for i := 0; i < 100; i++ {
+			l.Put(scan, scan)
+			lru.Put(scan, scan)
+			scan++
+		}
+	}
+
+	for i := 0; i < 50; i++ {
+		if !l.ContainsKey(i) {
+			t.Fatalf("hot key %v should not be evicted by keys used once", i)
+		}
+	}
+
+	stats := l.GetStats()
+	if stats.Hits < 95*50*95/100 || lru.GetStats().Hits != 0 {
+		t.Fatalf("Wrong Hits stat %v, the LRU one is %v", stats.Hits, lru.GetStats().Hits)
+	}
+
+	if stats.AdmissionRejections < 9000 || stats.AdmissionRejections > stats.Evictions {
+		t.Fatalf("Wrong AdmissionRejections %v or Evictions %v stat", stats.AdmissionRejections, stats.Evictions)
+	}
+}
+
+func TestGoriaZipf(t *testing.T) {
+
+	tinyLFU, err := New[uint64, uint64]("tinylfu", 500, nil, false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	lru, err := gorialru.New[uint64, uint64]("lru", 500, nil, false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	zipf := rand.NewZipf(rand.New(rand.NewSource(42)), 1.1, 1, 100000)
+	keys := make([]uint64, 200000)
+	for i := range keys {
+		keys[i] = zipf.Uint64()
+	}
+
+	ratio := func(c goria.Cache[uint64, uint64]) float64 {
+		hits := 0
+		for _, k := range keys {
+			if _, ok := c.Get(k); ok {
+				hits++
+			} else {
+				c.Put(k, k)
+			}
+		}
+		return float64(hits) / float64(len(keys))
+	}
+
+	tinyLFURatio, lruRatio := ratio(tinyLFU), ratio(lru)
+	t.Logf("W-TinyLFU hit ratio %v, LRU hit ratio %v", tinyLFURatio, lruRatio)
+
+	if tinyLFURatio <= lruRatio {
+		t.Fatalf("W-TinyLFU hit ratio %v should beat the LRU one %v", tinyLFURatio, lruRatio)
+	}
+}
@@ -0,0 +1,119 @@
+package goriatinylfu
+
+import (
+	"hash/maphash"
+	"math/bits"
+
+	"github.com/oscerd/goria/internal/keyhash"
+)
+
+const (
+	sketchDepth    = 4
+	maxCount       = 15
+	doorkeeperHash = 2
+)
+
+// sketch estimates how often a key has been used lately with a count-min sketch of saturating 4 bit counters,
+// every counter being halved once sampleSize uses have been recorded so that old uses fade out.
+// The doorkeeper is a bloom filter recording the keys used once since the last aging, so that the keys used
+// a single time never reach the counters.
+type sketch[K comparable] struct {
+	seed       maphash.Seed
+	rows       [sketchDepth][]uint8
+	mask       uint64
+	doorkeeper []uint64
+	doorMask   uint64
+	additions  int
+	sampleSize int
+}
+
+func newSketch[K comparable](size int) *sketch[K] {
+	width := nextPowerOfTwo(max(size, 16))
+	s := &sketch[K]{
+		seed:       maphash.MakeSeed(),
+		mask:       uint64(width - 1),
+		doorkeeper: make([]uint64, width*4/64),
+		doorMask:   uint64(width*4 - 1),
+		sampleSize: 10 * max(size, 16),
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// increment records a use of key.
+func (s *sketch[K]) increment(key K) {
+	h := keyhash.Sum(s.seed, key)
+	if !s.admitted(h) {
+		return
+	}
+	for i := range s.rows {
+		if counter := &s.rows[i][s.index(h, i)]; *counter < maxCount {
+			*counter++
+		}
+	}
+	s.additions++
+	if s.additions >= s.sampleSize {
+		s.reset()
+	}
+}
+
+// estimate returns how often key has been used lately, counting the use recorded by the doorkeeper.
+func (s *sketch[K]) estimate(key K) int {
+	h := keyhash.Sum(s.seed, key)
+	count := uint8(maxCount)
+	for i := range s.rows {
+		count = min(count, s.rows[i][s.index(h, i)])
+	}
+	if s.contains(h) {
+		return int(count) + 1
+	}
+	return int(count)
+}
+
+// reset halves every counter and empties the doorkeeper.
+func (s *sketch[K]) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	clear(s.doorkeeper)
+	s.additions /= 2
+}
+
+// admitted records h in the doorkeeper, returning whether it was already there.
+func (s *sketch[K]) admitted(h uint64) bool {
+	if s.contains(h) {
+		return true
+	}
+	for i := 0; i < doorkeeperHash; i++ {
+		bit := s.doorkeeperBit(h, i)
+		s.doorkeeper[bit/64] |= 1 << (bit % 64)
+	}
+	return false
+}
+
+func (s *sketch[K]) contains(h uint64) bool {
+	for i := 0; i < doorkeeperHash; i++ {
+		bit := s.doorkeeperBit(h, i)
+		if s.doorkeeper[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// index and doorkeeperBit derive the positions of a key from its hash by double hashing.
+func (s *sketch[K]) index(h uint64, i int) uint64 {
+	return (h + uint64(i)*(h>>32|1)) & s.mask
+}
+
+func (s *sketch[K]) doorkeeperBit(h uint64, i int) uint64 {
+	return (bits.RotateLeft64(h, 17) + uint64(i+sketchDepth)*(h>>32|1)) & s.doorMask
+}
+
+func nextPowerOfTwo(n int) int {
+	return 1 << bits.Len(uint(n-1))
+}
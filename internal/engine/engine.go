@@ -0,0 +1,981 @@
+/*
+Package engine provides the cache shared by the Goria eviction policies, every
+policy package wrapping a Cache built around its own goria.EvictionPolicy.
+
+A Cache is safe for concurrent use by multiple goroutines, the policy, the
+eviction callback and the entry listeners are invoked while the cache lock is
+held so they must not call back into the cache.
+*/
+package engine
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/oscerd/goria"
+)
+
+type Cache[K comparable, V any] struct {
+	Name         string
+	size         int
+	maxWeight    int64
+	lock         sync.Mutex
+	items        map[K]*entry[K, V]
+	policy       goria.EvictionPolicy[K]
+	protected    K
+	protecting   bool
+	evictable    func(key K) bool
+	onEvict      goria.EvictionCallback[K, V]
+	expiryPolicy goria.ExpiryPolicy
+	loader       goria.CacheLoader[K, V]
+	readThrough  bool
+	writer       goria.CacheWriter[K, V]
+	weigher      goria.Weigher[K, V]
+	weight       int64
+	expiring     int
+	recorder     goria.MetricsRecorder
+	listeners    []listenerRegistration[K, V]
+	listenerID   uint64
+	now          func() time.Time
+	statsEnabled atomic.Bool
+	stats        goria.CacheStats
+}
+
+var _ goria.Cache[string, interface{}] = (*Cache[string, interface{}])(nil)
+
+type listenerRegistration[K comparable, V any] struct {
+	id       uint64
+	listener goria.CacheEntryListener[K, V]
+	filter   goria.CacheEntryEventFilter[K, V]
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	weight    int64
+	expiresAt time.Time
+}
+
+func New[K comparable, V any](name string, config goria.Configuration[K, V], policy goria.EvictionPolicy[K]) (*Cache[K, V], error) {
+	if config.Size <= 0 {
+		return nil, goria.ErrInvalidSize
+	}
+	expiryPolicy := config.ExpiryPolicy
+	if expiryPolicy == nil {
+		expiryPolicy = goria.NewEternalExpiryPolicy()
+	}
+	var writer goria.CacheWriter[K, V]
+	if config.WriteThrough {
+		writer = config.CacheWriter
+	}
+	c := &Cache[K, V]{
+		Name:         name,
+		size:         config.Size,
+		maxWeight:    config.MaxWeight,
+		items:        make(map[K]*entry[K, V]),
+		policy:       policy,
+		onEvict:      config.EvictionCallback,
+		expiryPolicy: expiryPolicy,
+		loader:       config.CacheLoader,
+		readThrough:  config.ReadThrough && config.CacheLoader != nil,
+		writer:       writer,
+		weigher:      config.Weigher,
+		recorder:     config.MetricsRecorder,
+		now:          time.Now,
+	}
+	c.evictable = c.canEvict
+	c.statsEnabled.Store(config.StatsEnabled)
+	return c, nil
+}
+
+// SetClock replaces the clock of c, so that the tests of the policy packages can control expiry.
+func SetClock[K comparable, V any](c *Cache[K, V], now func() time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.now = now
+}
+
+// Resize changes the size of c, evicting at once the entries in excess through the eviction callback
+// and returning how many were evicted. It is exported by the policy packages whose policy does not
+// depend on the size of the cache.
+func Resize[K comparable, V any](c *Cache[K, V], newSize int) (int, error) {
+	if newSize <= 0 {
+		return 0, goria.ErrInvalidSize
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.size = newSize
+	length := len(c.items)
+	err := c.evict()
+	return length - len(c.items), err
+}
+
+// Put stores the entry, the error of the CacheWriter is returned and the cache left untouched when the write fails.
+func (c *Cache[K, V]) Put(key K, value V) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	defer c.measure(&c.stats.PutTime, &c.stats.PutLatency, c.start(&c.stats.Puts))
+
+	if err := c.write(key, value); err != nil {
+		return err
+	}
+	return c.put(key, value)
+}
+
+// PutWithTTL stores the entry with a time to live of ttl, overriding the expiry policy of the cache.
+func (c *Cache[K, V]) PutWithTTL(key K, value V, ttl time.Duration) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	defer c.measure(&c.stats.PutTime, &c.stats.PutLatency, c.start(&c.stats.Puts))
+
+	if err := c.write(key, value); err != nil {
+		return err
+	}
+	return c.putWithExpiry(key, value, ttl, ttl)
+}
+
+func (c *Cache[K, V]) PutAll(m map[K]V) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	defer c.measure(&c.stats.PutTime, &c.stats.PutLatency, c.start(&c.stats.Puts))
+
+	for key, value := range m {
+		if err := c.fits(key, value); err != nil {
+			return err
+		}
+	}
+	if c.writer != nil {
+		if err := c.writer.WriteAll(m); err != nil {
+			return err
+		}
+	}
+	if c.IsStatsEnabled() {
+		c.stats.Puts += int64(len(m))
+	}
+	for key, value := range m {
+		if err := c.put(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Cache[K, V]) PutIfAbsent(key K, value V) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	defer c.measure(&c.stats.PutTime, &c.stats.PutLatency, c.start(&c.stats.Puts))
+
+	if _, exists := c.lookup(key); exists {
+		return false, nil
+	}
+	if err := c.write(key, value); err != nil {
+		return false, err
+	}
+	if err := c.put(key, value); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *Cache[K, V]) Get(key K) (value V, exists bool) {
+	value, exists, _ = c.GetOrLoad(key)
+	return
+}
+
+// GetOrLoad behaves like Get, returning the error of the CacheLoader when a miss could not be read through.
+func (c *Cache[K, V]) GetOrLoad(key K) (value V, exists bool, err error) {
+	c.lock.Lock()
+	start := c.start(&c.stats.Gets)
+	value, exists = c.get(key)
+	if exists || !c.readThrough {
+		c.measure(&c.stats.GetTime, &c.stats.GetLatency, start)
+		c.lock.Unlock()
+		return value, exists, nil
+	}
+	gets := start.suspend()
+	c.lock.Unlock()
+
+	value, exists, err = c.load(key)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	start.resume(gets)
+	defer c.measure(&c.stats.GetTime, &c.stats.GetLatency, start)
+
+	var zero V
+	if err != nil {
+		if c.IsStatsEnabled() {
+			c.stats.LoadErrors++
+		}
+		return zero, false, err
+	}
+	if !exists {
+		return zero, false, nil
+	}
+	if c.IsStatsEnabled() {
+		c.stats.Loads++
+	}
+	if value, err = c.putLoaded(key, value); err != nil {
+		return zero, false, err
+	}
+	return value, true, nil
+}
+
+func (c *Cache[K, V]) GetAll(keys []K) map[K]V {
+	returnedMap, _ := c.GetAllOrLoad(keys)
+	return returnedMap
+}
+
+// GetAllOrLoad behaves like GetAll, returning the error of the CacheLoader when the misses could not be read through.
+func (c *Cache[K, V]) GetAllOrLoad(keys []K) (map[K]V, error) {
+	returnedMap := make(map[K]V)
+	var missing []K
+
+	c.lock.Lock()
+	start := c.start(&c.stats.Gets)
+	for _, k := range keys {
+		value, exists := c.get(k)
+		if exists {
+			returnedMap[k] = value
+		} else {
+			missing = append(missing, k)
+		}
+	}
+	if len(missing) == 0 || !c.readThrough {
+		c.measure(&c.stats.GetTime, &c.stats.GetLatency, start)
+		c.lock.Unlock()
+		return returnedMap, nil
+	}
+	gets := start.suspend()
+	c.lock.Unlock()
+
+	loaded, err := c.loadKeys(missing)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	start.resume(gets)
+	defer c.measure(&c.stats.GetTime, &c.stats.GetLatency, start)
+
+	if err != nil {
+		if c.IsStatsEnabled() {
+			c.stats.LoadErrors++
+		}
+		return returnedMap, err
+	}
+	for k, value := range loaded {
+		if c.IsStatsEnabled() {
+			c.stats.Loads++
+		}
+		value, err := c.putLoaded(k, value)
+		if err != nil {
+			return returnedMap, err
+		}
+		returnedMap[k] = value
+	}
+	return returnedMap, nil
+}
+
+// LoadAll asynchronously loads keys through the CacheLoader, even when the cache is not read through,
+// keys already in the cache are loaded again only when replaceExisting is set. completion may be nil.
+func (c *Cache[K, V]) LoadAll(keys []K, replaceExisting bool, completion goria.CompletionListener) {
+	go func() {
+		err := c.loadAll(keys, replaceExisting)
+		if completion != nil {
+			completion(err)
+		}
+	}()
+}
+
+// Replace replaces the value of key only when it is equal to oldValue, it panics if V is not comparable.
+func (c *Cache[K, V]) Replace(key K, oldValue V, newValue V) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	defer c.measure(&c.stats.PutTime, &c.stats.PutLatency, c.start(&c.stats.Puts))
+
+	var e, exists = c.lookup(key)
+	c.count(exists)
+	if exists && equal(e.value, oldValue) {
+		if err := c.write(key, newValue); err != nil {
+			return false, err
+		}
+		if err := c.update(e, newValue, c.expiryPolicy.ExpiryForUpdate()); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (c *Cache[K, V]) ReplaceWithKeyOnly(key K, newValue V) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	defer c.measure(&c.stats.PutTime, &c.stats.PutLatency, c.start(&c.stats.Puts))
+
+	_, exists := c.lookup(key)
+	c.count(exists)
+	return c.replace(key, newValue)
+}
+
+func (c *Cache[K, V]) GetAndReplace(key K, newValue V) (V, bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	start := c.start(&c.stats.Gets)
+	v, ok := c.get(key)
+	c.measure(&c.stats.GetTime, &c.stats.GetLatency, start)
+	if ok {
+		defer c.measure(&c.stats.PutTime, &c.stats.PutLatency, c.start(&c.stats.Puts))
+		if _, err := c.replace(key, newValue); err != nil {
+			var zero V
+			return zero, false, err
+		}
+	}
+	return v, ok, nil
+}
+
+// RemoveWithKeyOnly removes the entry, the CacheWriter is asked to delete the key even when it is not in the cache.
+func (c *Cache[K, V]) RemoveWithKeyOnly(key K) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	defer c.measure(&c.stats.RemoveTime, &c.stats.RemoveLatency, c.start(&c.stats.Removals))
+
+	return c.remove(key)
+}
+
+// Remove removes the entry of key only when its value is equal to oldValue, it panics if V is not comparable.
+func (c *Cache[K, V]) Remove(key K, oldValue V) (bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	defer c.measure(&c.stats.RemoveTime, &c.stats.RemoveLatency, c.start(&c.stats.Removals))
+
+	var e, exists = c.lookup(key)
+	c.count(exists)
+	if exists && equal(e.value, oldValue) {
+		if err := c.delete(key); err != nil {
+			return false, err
+		}
+		c.removeEntry(e, goria.Removed)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (c *Cache[K, V]) RemoveAll(m map[K]V) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	defer c.measure(&c.stats.RemoveTime, &c.stats.RemoveLatency, c.start(&c.stats.Removals))
+
+	var entries []*entry[K, V]
+	var keys []K
+	for key, value := range m {
+		if e, exists := c.lookup(key); exists && equal(e.value, value) {
+			entries = append(entries, e)
+			keys = append(keys, key)
+		}
+	}
+	return c.removeEntries(entries, keys)
+}
+
+func (c *Cache[K, V]) RemoveAllWithoutParameters() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	defer c.measure(&c.stats.RemoveTime, &c.stats.RemoveLatency, c.start(&c.stats.Removals))
+
+	c.removeExpired()
+	keys := c.keys()
+	entries := make([]*entry[K, V], len(keys))
+	for i, key := range keys {
+		entries[i] = c.items[key]
+	}
+	return c.removeEntries(entries, keys)
+}
+
+// GetAndRemove removes the entry returning its value, the CacheWriter is asked to delete the key even when it is not in the cache.
+func (c *Cache[K, V]) GetAndRemove(key K) (V, bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	start := c.start(&c.stats.Gets)
+	v, ok := c.get(key)
+	c.measure(&c.stats.GetTime, &c.stats.GetLatency, start)
+	defer c.measure(&c.stats.RemoveTime, &c.stats.RemoveLatency, c.start(&c.stats.Removals))
+	if _, err := c.remove(key); err != nil {
+		var zero V
+		return zero, false, err
+	}
+	return v, ok, nil
+}
+
+// Clear empties the cache without invoking the eviction callback.
+func (c *Cache[K, V]) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for key := range c.items {
+		c.policy.OnRemove(key)
+	}
+	c.items = make(map[K]*entry[K, V])
+	c.weight = 0
+	c.expiring = 0
+}
+
+// Invoke runs processor on the entry of key while holding the cache lock, returning the result of the processor.
+func (c *Cache[K, V]) Invoke(key K, processor goria.EntryProcessor[K, V], args ...interface{}) (interface{}, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.invoke(key, processor, args...)
+}
+
+// InvokeAll runs processor on the entry of every key, only the keys with a non nil result or an error are returned.
+func (c *Cache[K, V]) InvokeAll(keys []K, processor goria.EntryProcessor[K, V], args ...interface{}) map[K]goria.EntryProcessorResult {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	results := make(map[K]goria.EntryProcessorResult)
+	for _, key := range keys {
+		value, err := c.invoke(key, processor, args...)
+		if value != nil || err != nil {
+			results[key] = goria.EntryProcessorResult{Value: value, Err: err}
+		}
+	}
+	return results
+}
+
+// RegisterCacheEntryListener registers listener for the events accepted by filter, a nil filter accepting every event.
+// The returned id deregisters the listener.
+func (c *Cache[K, V]) RegisterCacheEntryListener(listener goria.CacheEntryListener[K, V], filter goria.CacheEntryEventFilter[K, V]) uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.listenerID++
+	c.listeners = append(c.listeners, listenerRegistration[K, V]{c.listenerID, listener, filter})
+	return c.listenerID
+}
+
+func (c *Cache[K, V]) DeregisterCacheEntryListener(id uint64) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for i, registration := range c.listeners {
+		if registration.id == id {
+			c.listeners = append(c.listeners[:i:i], c.listeners[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns the keys from the next victim on, when the policy is a goria.OrderedEvictionPolicy.
+func (c *Cache[K, V]) Keys() []K {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.removeExpired()
+	return c.keys()
+}
+
+func (c *Cache[K, V]) ContainsKey(key K) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	_, exists := c.lookup(key)
+	return exists
+}
+
+func (c *Cache[K, V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.removeExpired()
+	return len(c.items)
+}
+
+func (c *Cache[K, V]) GetName() string {
+	return c.Name
+}
+
+// GetSize returns the maximum number of entries of the cache, as changed by Resize.
+func (c *Cache[K, V]) GetSize() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.size
+}
+
+// GetMaxWeight returns the maximum weight of the cache, 0 when the cache is bounded by Size alone.
+func (c *Cache[K, V]) GetMaxWeight() int64 {
+	return c.maxWeight
+}
+
+func (c *Cache[K, V]) IsStatsEnabled() bool {
+	return c.statsEnabled.Load()
+}
+
+// SetStatisticsEnabled switches the collection of statistics on or off, the counters collected so far being kept.
+func (c *Cache[K, V]) SetStatisticsEnabled(enabled bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.statsEnabled.Store(enabled)
+}
+
+// ClearStatistics resets every counter, Items, Weight and Capacity keeping the state of the cache.
+func (c *Cache[K, V]) ClearStatistics() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.stats = goria.CacheStats{}
+}
+
+// GetStats returns a snapshot of the statistics taken while holding the cache lock, so that its counters are
+// consistent with each other. Items, Weight and Capacity are reported even while statistics are disabled.
+func (c *Cache[K, V]) GetStats() goria.CacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	stats := c.stats
+	stats.Items = int64(len(c.items))
+	stats.Weight = c.weight
+	stats.Capacity = int64(c.size)
+	return stats
+}
+
+func (c *Cache[K, V]) loadAll(keys []K, replaceExisting bool) error {
+	if c.loader == nil {
+		return goria.ErrNoLoader
+	}
+
+	c.lock.Lock()
+	var toLoad []K
+	for _, k := range keys {
+		if _, exists := c.lookup(k); replaceExisting || !exists {
+			toLoad = append(toLoad, k)
+		}
+	}
+	c.lock.Unlock()
+
+	if len(toLoad) == 0 {
+		return nil
+	}
+
+	loaded, err := c.loadKeys(toLoad)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err != nil {
+		if c.IsStatsEnabled() {
+			c.stats.LoadErrors++
+		}
+		return err
+	}
+	for k, value := range loaded {
+		if c.IsStatsEnabled() {
+			c.stats.Loads++
+		}
+		if replaceExisting {
+			err = c.put(k, value)
+		} else {
+			_, err = c.putLoaded(k, value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Cache[K, V]) invoke(key K, processor goria.EntryProcessor[K, V], args ...interface{}) (interface{}, error) {
+	e := &mutableEntry[K, V]{cache: c, key: key}
+	if item, exists := c.lookup(key); exists {
+		e.value, e.exists = item.value, true
+	}
+
+	result, err := processor(e, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.apply(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// put, get, replace and remove expect the caller to hold the lock.
+func (c *Cache[K, V]) put(key K, value V) error {
+	return c.putWithExpiry(key, value, c.expiryPolicy.ExpiryForCreation(), c.expiryPolicy.ExpiryForUpdate())
+}
+
+// putWithExpiry leaves out the loaded values heavier than the maximum weight, the others being checked by write.
+func (c *Cache[K, V]) putWithExpiry(key K, value V, creation, update time.Duration) error {
+	if c.fits(key, value) != nil {
+		return nil
+	}
+	if item, ok := c.lookup(key); ok {
+		return c.update(item, value, update)
+	}
+
+	if creation != goria.Eternal && creation <= 0 {
+		return nil
+	}
+
+	item := &entry[K, V]{key: key, value: value, weight: c.weigh(key, value)}
+	c.setExpiry(item, c.expiration(creation))
+	c.items[key] = item
+	c.weight += item.weight
+	c.policy.OnInsert(key)
+	var zero V
+	c.fire(goria.Created, key, value, zero)
+
+	return c.evictFor(key)
+}
+
+func (c *Cache[K, V]) update(e *entry[K, V], value V, d time.Duration) error {
+	c.policy.OnAccess(e.key)
+	oldValue := e.value
+	e.value = value
+	c.weight -= e.weight
+	e.weight = c.weigh(e.key, value)
+	c.weight += e.weight
+	c.updateExpiry(e, d)
+	c.fire(goria.Updated, e.key, value, oldValue)
+	return c.evictFor(e.key)
+}
+
+// putLoaded stores a loaded value unless another one was put meanwhile, returning the value held by the cache.
+func (c *Cache[K, V]) putLoaded(key K, value V) (V, error) {
+	if item, exists := c.lookup(key); exists {
+		return item.value, nil
+	}
+	return value, c.put(key, value)
+}
+
+func (c *Cache[K, V]) get(key K) (value V, exists bool) {
+	item, exists := c.lookup(key)
+	c.count(exists)
+	if !exists {
+		return
+	}
+	c.policy.OnAccess(key)
+	c.updateExpiry(item, c.expiryPolicy.ExpiryForAccess())
+	return item.value, true
+}
+
+func (c *Cache[K, V]) replace(key K, newValue V) (bool, error) {
+	var item, exists = c.lookup(key)
+	if exists {
+		if err := c.write(key, newValue); err != nil {
+			return false, err
+		}
+		if err := c.update(item, newValue, c.expiryPolicy.ExpiryForUpdate()); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+func (c *Cache[K, V]) remove(key K) (bool, error) {
+	if err := c.delete(key); err != nil {
+		return false, err
+	}
+	if item, exists := c.lookup(key); exists {
+		c.removeEntry(item, goria.Removed)
+		return true, nil
+	}
+	return false, nil
+}
+
+func (c *Cache[K, V]) removeEntries(entries []*entry[K, V], keys []K) error {
+	if c.writer != nil && len(keys) > 0 {
+		if err := c.writer.DeleteAll(keys); err != nil {
+			return err
+		}
+	}
+	for _, e := range entries {
+		c.removeEntry(e, goria.Removed)
+	}
+	return nil
+}
+
+// write checks that the entry fits the cache, then write and delete forward a mutation to the CacheWriter,
+// when the cache is write through. write is called before every put of an API method, which it counts.
+func (c *Cache[K, V]) write(key K, value V) error {
+	if err := c.fits(key, value); err != nil {
+		return err
+	}
+	if c.writer != nil {
+		if err := c.writer.Write(key, value); err != nil {
+			return err
+		}
+	}
+	if c.IsStatsEnabled() {
+		c.stats.Puts++
+	}
+	return nil
+}
+
+func (c *Cache[K, V]) delete(key K) error {
+	if c.writer == nil {
+		return nil
+	}
+	return c.writer.Delete(key)
+}
+
+// lookup returns the entry stored under key, an expired entry is removed and reported as absent.
+func (c *Cache[K, V]) lookup(key K) (*entry[K, V], bool) {
+	item, exists := c.items[key]
+	if !exists {
+		return nil, false
+	}
+	if c.isExpired(item) {
+		c.removeEntry(item, goria.Expired)
+		return nil, false
+	}
+	return item, true
+}
+
+// removeExpired removes every expired entry, walking the cache only when some entries can expire.
+func (c *Cache[K, V]) removeExpired() {
+	if c.expiring == 0 {
+		return
+	}
+	for _, key := range c.keys() {
+		if item := c.items[key]; c.isExpired(item) {
+			c.removeEntry(item, goria.Expired)
+		}
+	}
+}
+
+// keys returns the keys in the order of the policy, when it is an OrderedPolicy.
+func (c *Cache[K, V]) keys() []K {
+	if ordered, ok := c.policy.(goria.OrderedEvictionPolicy[K]); ok {
+		return ordered.Keys()
+	}
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (c *Cache[K, V]) isExpired(e *entry[K, V]) bool {
+	return !e.expiresAt.IsZero() && !c.now().Before(e.expiresAt)
+}
+
+// expiration turns a duration of the expiry policy into a deadline, the zero time meaning eternal.
+func (c *Cache[K, V]) expiration(d time.Duration) time.Time {
+	if d == goria.Eternal {
+		return time.Time{}
+	}
+	if d < 0 {
+		d = 0
+	}
+	return c.now().Add(d)
+}
+
+func (c *Cache[K, V]) updateExpiry(e *entry[K, V], d time.Duration) {
+	if d != goria.Unchanged {
+		c.setExpiry(e, c.expiration(d))
+	}
+}
+
+// setExpiry sets the deadline of e, counting the entries which are not eternal.
+func (c *Cache[K, V]) setExpiry(e *entry[K, V], expiresAt time.Time) {
+	if e.expiresAt.IsZero() != expiresAt.IsZero() {
+		if expiresAt.IsZero() {
+			c.expiring--
+		} else {
+			c.expiring++
+		}
+	}
+	e.expiresAt = expiresAt
+}
+
+// evict removes the victims of the policy until the cache fits its size and its maximum weight, returning
+// goria.ErrNoVictim when the policy does not return a key of the cache, the cache being left over capacity.
+func (c *Cache[K, V]) evict() error {
+	for len(c.items) > c.size || c.overweight() {
+		key, ok := c.policy.Victim(c.evictable)
+		item, exists := c.items[key]
+		if !ok || !exists || !c.evictable(key) {
+			return fmt.Errorf("%w: %v", goria.ErrNoVictim, c.Name)
+		}
+		if admission, ok := c.policy.(goria.AdmissionEvictionPolicy[K]); ok && admission.Rejected() && c.IsStatsEnabled() {
+			c.stats.AdmissionRejections++
+		}
+		c.removeEntry(item, goria.Evicted)
+	}
+	return nil
+}
+
+// evictFor evicts the entries in excess after key was inserted or updated, key being left out of the victims.
+func (c *Cache[K, V]) evictFor(key K) error {
+	c.protected, c.protecting = key, true
+	defer func() {
+		var zero K
+		c.protected, c.protecting = zero, false
+	}()
+	return c.evict()
+}
+
+// canEvict tells the policy whether key may be evicted, only the key being inserted or updated is not.
+// It is given to the policy through the evictable field, so that no closure is allocated by evict.
+func (c *Cache[K, V]) canEvict(key K) bool {
+	return !c.protecting || key != c.protected
+}
+
+// removeEntry removes e from the cache, reason is one of goria.Removed, goria.Expired or goria.Evicted.
+func (c *Cache[K, V]) removeEntry(e *entry[K, V], reason goria.EventType) {
+	delete(c.items, e.key)
+	c.weight -= e.weight
+	if !e.expiresAt.IsZero() {
+		c.expiring--
+	}
+	c.policy.OnRemove(e.key)
+
+	if reason == goria.Evicted && c.onEvict != nil {
+		c.onEvict(e.key, e.value)
+	}
+	c.fire(reason, e.key, e.value, e.value)
+
+	if c.recorder != nil && reason != goria.Removed {
+		c.recorder.RecordEviction(c.Name, reason)
+	}
+	if c.IsStatsEnabled() {
+		switch reason {
+		case goria.Removed:
+			c.stats.Removals++
+		case goria.Expired:
+			c.stats.Expirations++
+		default:
+			c.stats.Evictions++
+		}
+	}
+}
+
+// count counts a read of the cache, finding its key or not.
+func (c *Cache[K, V]) count(hit bool) {
+	if c.recorder != nil {
+		if hit {
+			c.recorder.RecordHit(c.Name)
+		} else {
+			c.recorder.RecordMiss(c.Name)
+		}
+	}
+	if !c.IsStatsEnabled() {
+		return
+	}
+	c.stats.Gets++
+	if hit {
+		c.stats.Hits++
+	} else {
+		c.stats.Miss++
+	}
+}
+
+// measurement is the start of an operation, along with the value of the counter of its kind of operation at that time.
+type measurement struct {
+	start   time.Time
+	count   *int64
+	counted int64
+}
+
+// start returns the measurement of an operation counted by count, the time being taken when statistics are enabled.
+func (c *Cache[K, V]) start(count *int64) measurement {
+	m := measurement{count: count, counted: *count}
+	if c.IsStatsEnabled() {
+		m.start = c.now()
+	}
+	return m
+}
+
+// suspend returns the operations counted since m started, before the lock is released.
+func (m *measurement) suspend() int64 {
+	return *m.count - m.counted
+}
+
+// resume leaves out of m the operations counted by other goroutines while the lock was released,
+// counted being the operations returned by suspend.
+func (m *measurement) resume(counted int64) {
+	m.counted = *m.count - counted
+}
+
+// measure adds the time elapsed since m started to total, when statistics are enabled, the time being split
+// evenly across the operations counted since then, each observed by histogram. An operation that counted
+// nothing, such as the removal of an absent key, is not measured, nor is one started while statistics were
+// disabled, which has no start time.
+func (c *Cache[K, V]) measure(total *time.Duration, histogram *goria.LatencyHistogram, m measurement) {
+	n := *m.count - m.counted
+	if !c.IsStatsEnabled() || m.start.IsZero() || n <= 0 {
+		return
+	}
+	d := c.now().Sub(m.start)
+	*total += d
+	for i := int64(0); i < n; i++ {
+		histogram.Observe(d / time.Duration(n))
+	}
+}
+
+// load and loadKeys call the CacheLoader, telling the MetricsRecorder how long it took.
+func (c *Cache[K, V]) load(key K) (V, bool, error) {
+	if c.recorder == nil {
+		return c.loader.Load(key)
+	}
+	start := time.Now()
+	value, exists, err := c.loader.Load(key)
+	c.recorder.RecordLoad(c.Name, time.Since(start), err)
+	return value, exists, err
+}
+
+func (c *Cache[K, V]) loadKeys(keys []K) (map[K]V, error) {
+	if c.recorder == nil {
+		return c.loader.LoadAll(keys)
+	}
+	start := time.Now()
+	loaded, err := c.loader.LoadAll(keys)
+	c.recorder.RecordLoad(c.Name, time.Since(start), err)
+	return loaded, err
+}
+
+func (c *Cache[K, V]) fire(eventType goria.EventType, key K, value, oldValue V) {
+	if len(c.listeners) == 0 {
+		return
+	}
+	event := goria.CacheEntryEvent[K, V]{Type: eventType, Key: key, Value: value, OldValue: oldValue}
+	for _, registration := range c.listeners {
+		if registration.filter == nil || registration.filter(event) {
+			registration.listener(event)
+		}
+	}
+}
+
+// weigh returns the weight of an entry, 1 when the cache has no Weigher.
+func (c *Cache[K, V]) weigh(key K, value V) int64 {
+	if c.weigher == nil {
+		return 1
+	}
+	return c.weigher(key, value)
+}
+
+// fits returns goria.ErrEntryTooHeavy when the entry alone is heavier than the maximum weight of the cache.
+func (c *Cache[K, V]) fits(key K, value V) error {
+	if c.maxWeight > 0 && c.weigh(key, value) > c.maxWeight {
+		return fmt.Errorf("%w: %v", goria.ErrEntryTooHeavy, key)
+	}
+	return nil
+}
+
+func (c *Cache[K, V]) overweight() bool {
+	return c.maxWeight > 0 && c.weight > c.maxWeight
+}
+
+// equal compares two values the way interface{} values are compared, panicking on values that are not comparable.
+func equal[V any](a, b V) bool {
+	return interface{}(a) == interface{}(b)
+}
@@ -0,0 +1,1234 @@
+package engine
+
+import (
+	"container/list"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/oscerd/goria"
+)
+
+// lruPolicy evicts the least recently used key, so that the engine is tested the way gorialru is.
+type lruPolicy[K comparable] struct {
+	list     *list.List
+	elements map[K]*list.Element
+}
+
+func (p *lruPolicy[K]) OnAccess(key K) {
+	p.list.MoveToFront(p.elements[key])
+}
+
+func (p *lruPolicy[K]) OnInsert(key K) {
+	p.elements[key] = p.list.PushFront(key)
+}
+
+func (p *lruPolicy[K]) OnRemove(key K) {
+	p.list.Remove(p.elements[key])
+	delete(p.elements, key)
+}
+
+func (p *lruPolicy[K]) Victim(evictable func(key K) bool) (key K, ok bool) {
+	for element := p.list.Back(); element != nil; element = element.Prev() {
+		if key := element.Value.(K); evictable(key) {
+			return key, true
+		}
+	}
+	return
+}
+
+func (p *lruPolicy[K]) Keys() []K {
+	keys := make([]K, 0, p.list.Len())
+	for element := p.list.Back(); element != nil; element = element.Prev() {
+		keys = append(keys, element.Value.(K))
+	}
+	return keys
+}
+
+func newLRU[K comparable, V any](name string, size int, evictionC goria.EvictionCallback[K, V], statsEnabled bool) (*Cache[K, V], error) {
+	return newLRUWithConfiguration(name, goria.Configuration[K, V]{
+		Size:             size,
+		EvictionCallback: evictionC,
+		StatsEnabled:     statsEnabled,
+	})
+}
+
+func newLRUWithConfiguration[K comparable, V any](name string, config goria.Configuration[K, V]) (*Cache[K, V], error) {
+	return New(name, config, &lruPolicy[K]{list.New(), make(map[K]*list.Element)})
+}
+
+func TestGoria(t *testing.T) {
+
+	l, err := newLRU[int, int]("sample", 128, nil, true)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	for i := 0; i < 256; i++ {
+		l.Put(i, i)
+	}
+
+	if l.GetName() != "sample" {
+		t.Fatalf("Wrong name %v", l.GetName())
+	}
+
+	if l.Len() != 128 {
+		t.Fatalf("Wrong len %v", l.Len())
+	}
+
+	for i, k := range l.Keys() {
+		if v, ok := l.Get(k); !ok || v != k || v != i+128 {
+			t.Fatalf("wrong key: %v", k)
+		}
+	}
+
+	if l.GetStats().Items != 128 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 128 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	if l.Len() != 128 {
+		t.Fatalf("Wrong len %v", l.Len())
+	}
+
+	key, value := 253, 22
+	otherKey, oldValue, newValue := 279, 22, 47
+
+	var result, _ = l.PutIfAbsent(key, value)
+
+	if l.GetStats().Items != 128 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 128 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	if result {
+		t.Fatalf("key %v should be already be associated with a value", key)
+	}
+
+	result, _ = l.PutIfAbsent(otherKey, value)
+
+	if l.GetStats().Items != 128 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 129 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	if !result {
+		t.Fatalf("key %v should not be associated with a value", otherKey)
+	}
+
+	l.Replace(otherKey, oldValue, newValue)
+
+	result, _ = l.Replace(otherKey, newValue+1, newValue+2)
+
+	if l.GetStats().Items != 128 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 129 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	if result {
+		t.Fatalf("key %v should not be replaced with a value %v, since it's current value is %v and not %v", otherKey, newValue+2, newValue, newValue+1)
+	}
+
+	result, _ = l.ReplaceWithKeyOnly(otherKey, newValue+1)
+
+	if !result {
+		t.Fatalf("key %v should be replaced with a value %v", otherKey, newValue+1)
+	}
+
+	if l.GetStats().Items != 128 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 129 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	v, ok := l.Get(otherKey)
+
+	if ok && v != newValue+1 {
+		t.Fatalf("key %v should have a value of %v", otherKey, newValue)
+	}
+
+	res := l.items[otherKey]
+
+	if res.value != newValue+1 {
+		t.Fatalf("key %v should have a value of %v instead has a value of %v", otherKey, newValue, res.value)
+	}
+
+	result, _ = l.RemoveWithKeyOnly(otherKey)
+
+	if !result {
+		t.Fatalf("key %v should be removed", otherKey)
+	}
+
+	if l.GetStats().Items != 127 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 129 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	otherKey, oldValue = 252, 252
+	result, _ = l.Remove(otherKey, oldValue)
+
+	if !result {
+		t.Fatalf("key %v should be removed with a value %v", otherKey, oldValue)
+	}
+
+	if l.GetStats().Items != 126 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 129 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	otherKey = 248
+	var getAndRemoveResult, removed, _ = l.GetAndRemove(otherKey)
+
+	if getAndRemoveResult != 248 {
+		t.Fatalf("key %v should be removed with a value %v", otherKey, 248)
+	}
+
+	if l.GetStats().Items != 125 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 129 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	otherKey = 2900
+	getAndRemoveResult, removed, _ = l.GetAndRemove(otherKey)
+
+	if removed {
+		t.Fatalf("key %v should not be removed", otherKey)
+	}
+
+	if l.GetStats().Items != 125 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 129 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	otherKey, oldValue, newValue = 247, 247, 1200
+	var getAndReplaceResult, replaced, _ = l.GetAndReplace(otherKey, newValue)
+
+	if getAndReplaceResult != 247 {
+		t.Fatalf("key %v should be replaced with an original value of %v", otherKey, oldValue)
+	}
+
+	if l.GetStats().Items != 125 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 129 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	otherKey, oldValue, newValue = 2900, 247, 1200
+	getAndReplaceResult, replaced, _ = l.GetAndReplace(otherKey, newValue)
+
+	if replaced {
+		t.Fatalf("key %v should not be replaced", otherKey)
+	}
+
+	if l.GetStats().Items != 125 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 129 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	commits := map[int]int{
+		253: 24,
+		267: 22,
+		280: 21,
+		281: 23,
+	}
+	var newKey = 253
+	newValue = 24
+	l.PutAll(commits)
+
+	if l.GetStats().Items != 128 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 129 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	returnedCommits := l.GetAll([]int{253, 267, 280, 281})
+
+	for k, v := range commits {
+		if returnedCommits[k] != v {
+			t.Fatalf("key %v should have value %v", k, v)
+		}
+	}
+
+	v, ok = l.Get(newKey)
+
+	if v != newValue {
+		t.Fatalf("key %v should have value %v", newKey, newValue)
+	}
+
+	l.RemoveAll(commits)
+
+	if l.GetStats().Items != 124 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 129 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	v, ok = l.Get(newKey)
+
+	if ok {
+		t.Fatalf("key %v shouldn't be in the cache", newKey)
+	}
+
+	l.PutAll(commits)
+
+	ok = l.ContainsKey(267)
+
+	if !ok {
+		t.Fatalf("key %v should be in the cache", 267)
+	}
+
+	l.RemoveAllWithoutParameters()
+
+	if l.GetStats().Items != 0 {
+		t.Fatalf("Wrong Items stat %v", l.GetStats().Items)
+	}
+
+	if l.GetStats().Evictions != 129 {
+		t.Fatalf("Wrong Evictions stat %v", l.GetStats().Evictions)
+	}
+
+	if l.GetStats().Removals != 135 {
+		t.Fatalf("Wrong Removals stat %v", l.GetStats().Removals)
+	}
+
+	if l.GetStats().Puts != 268 {
+		t.Fatalf("Wrong Puts stat %v", l.GetStats().Puts)
+	}
+
+	if l.GetStats().Gets != 143 {
+		t.Fatalf("Wrong Gets stat %v", l.GetStats().Gets)
+	}
+
+	if l.GetStats().Hits != 140 {
+		t.Fatalf("Wrong Hits stat %v", l.GetStats().Hits)
+	}
+
+	if l.GetStats().Miss != 3 {
+		t.Fatalf("Wrong Miss stat %v", l.GetStats().Miss)
+	}
+
+	v, ok = l.Get(newKey)
+
+	if ok {
+		t.Fatalf("key %v shouldn't be in the cache", newKey)
+	}
+
+	if l.Len() != 0 {
+		t.Fatalf("Cache should be empty")
+	}
+
+}
+
+func TestGoriaConcurrent(t *testing.T) {
+
+	l, err := newLRU[interface{}, int]("sample", 64, nil, true)
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	var inserted int64
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				l.Put(i%128, i)
+				l.Get((i + g) % 128)
+				l.ContainsKey(i % 128)
+				l.GetAndReplace(i%128, g)
+				l.GetAndRemove((i + 1) % 128)
+				l.Keys()
+				l.Len()
+				l.GetStats()
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if l.Len() > 64 {
+		t.Fatalf("Wrong len %v", l.Len())
+	}
+
+	if l.GetStats().Gets != l.GetStats().Hits+l.GetStats().Miss {
+		t.Fatalf("Wrong Gets stat %v", l.GetStats().Gets)
+	}
+
+	l.Clear()
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			if ok, _ := l.PutIfAbsent("absent", g); ok {
+				atomic.AddInt64(&inserted, 1)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if inserted != 1 {
+		t.Fatalf("PutIfAbsent should succeed exactly once, succeeded %v times", inserted)
+	}
+
+	l.Put("counter", 0)
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				for {
+					v, _ := l.Get("counter")
+					if ok, _ := l.Replace("counter", v, v+1); ok {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if v, _ := l.Get("counter"); v != 800 {
+		t.Fatalf("counter should be %v instead is %v", 800, v)
+	}
+}
+
+func TestGoriaExpiry(t *testing.T) {
+
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	l, err := newLRUWithConfiguration("sample", goria.Configuration[int, int]{Size: 10, StatsEnabled: true, ExpiryPolicy: goria.NewCreatedExpiryPolicy(time.Minute)})
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.now = clock
+
+	l.Put(1, 1)
+	l.PutWithTTL(2, 2, time.Hour)
+	l.PutWithTTL(3, 3, goria.Eternal)
+	l.PutWithTTL(4, 4, 0)
+
+	if l.ContainsKey(4) {
+		t.Fatalf("key %v with a zero ttl shouldn't be stored", 4)
+	}
+
+	now = now.Add(30 * time.Second)
+
+	if v, ok := l.Get(1); !ok || v != 1 {
+		t.Fatalf("key %v shouldn't be expired yet", 1)
+	}
+
+	now = now.Add(31 * time.Second)
+
+	if _, ok := l.Get(1); ok {
+		t.Fatalf("key %v should be expired", 1)
+	}
+
+	if l.ContainsKey(1) {
+		t.Fatalf("key %v should be expired", 1)
+	}
+
+	if l.Len() != 2 {
+		t.Fatalf("Wrong len %v", l.Len())
+	}
+
+	now = now.Add(time.Hour)
+
+	if keys := l.Keys(); len(keys) != 1 || keys[0] != 3 {
+		t.Fatalf("Wrong keys %v", keys)
+	}
+
+	if l.GetStats().Miss != 1 || l.GetStats().Items != 1 {
+		t.Fatalf("Wrong stats %v", l.GetStats())
+	}
+
+	if l.expiring != 0 {
+		t.Fatalf("only eternal entries are left, got %v expiring", l.expiring)
+	}
+
+	l.PutWithTTL(3, 3, time.Minute)
+	l.PutWithTTL(5, 5, time.Minute)
+	l.Replace(5, 5, 6)
+	l.PutWithTTL(3, 3, goria.Eternal)
+
+	if l.expiring != 1 {
+		t.Fatalf("Wrong expiring count %v", l.expiring)
+	}
+
+	l.RemoveWithKeyOnly(5)
+
+	if l.expiring != 0 {
+		t.Fatalf("Wrong expiring count %v", l.expiring)
+	}
+
+	policies := []struct {
+		name           string
+		policy         goria.ExpiryPolicy
+		expiredAccess  bool
+		expiredUpdated bool
+	}{
+		{"created", goria.NewCreatedExpiryPolicy(time.Minute), true, true},
+		{"accessed", goria.NewAccessedExpiryPolicy(time.Minute), false, true},
+		{"modified", goria.NewModifiedExpiryPolicy(time.Minute), true, false},
+		{"touched", goria.NewTouchedExpiryPolicy(time.Minute), false, false},
+		{"eternal", goria.NewEternalExpiryPolicy(), false, false},
+	}
+
+	for _, p := range policies {
+		c, err := newLRUWithConfiguration(p.name, goria.Configuration[string, int]{Size: 10, ExpiryPolicy: p.policy})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		c.now = clock
+
+		c.Put("accessed", 1)
+		c.Put("updated", 1)
+
+		now = now.Add(40 * time.Second)
+		c.Get("accessed")
+		c.Put("updated", 2)
+		now = now.Add(40 * time.Second)
+
+		if c.ContainsKey("accessed") == p.expiredAccess {
+			t.Fatalf("policy %v: accessed entry expired should be %v", p.name, p.expiredAccess)
+		}
+
+		if c.ContainsKey("updated") == p.expiredUpdated {
+			t.Fatalf("policy %v: updated entry expired should be %v", p.name, p.expiredUpdated)
+		}
+	}
+}
+
+type testLoader struct {
+	fail bool
+}
+
+func (l *testLoader) Load(key int) (int, bool, error) {
+	if l.fail {
+		return 0, false, errors.New("load failed")
+	}
+	if key < 0 {
+		return 0, false, nil
+	}
+	return key * 10, true, nil
+}
+
+func (l *testLoader) LoadAll(keys []int) (map[int]int, error) {
+	if l.fail {
+		return nil, errors.New("load all failed")
+	}
+	loaded := make(map[int]int)
+	for _, k := range keys {
+		if k >= 0 {
+			loaded[k] = k * 10
+		}
+	}
+	return loaded, nil
+}
+
+func TestGoriaLoader(t *testing.T) {
+
+	loader := &testLoader{}
+	l, err := newLRUWithConfiguration("sample", goria.Configuration[int, int]{Size: 10, StatsEnabled: true, CacheLoader: loader, ReadThrough: true})
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if v, ok := l.Get(1); !ok || v != 10 {
+		t.Fatalf("key %v should be loaded with a value of %v", 1, 10)
+	}
+
+	if !l.ContainsKey(1) {
+		t.Fatalf("key %v should be in the cache", 1)
+	}
+
+	if _, ok, err := l.GetOrLoad(-1); ok || err != nil {
+		t.Fatalf("key %v shouldn't be loaded", -1)
+	}
+
+	returned := l.GetAll([]int{1, 2, -2})
+	if len(returned) != 2 || returned[1] != 10 || returned[2] != 20 {
+		t.Fatalf("Wrong loaded values %v", returned)
+	}
+
+	loader.fail = true
+
+	if _, _, err := l.GetOrLoad(3); err == nil {
+		t.Fatalf("key %v should fail to load", 3)
+	}
+
+	if _, err := l.GetAllOrLoad([]int{4}); err == nil {
+		t.Fatalf("key %v should fail to load", 4)
+	}
+
+	if l.GetStats().Loads != 2 || l.GetStats().LoadErrors != 2 {
+		t.Fatalf("Wrong load stats %v", l.GetStats())
+	}
+
+	loader.fail = false
+	l.Put(5, 5)
+
+	done := make(chan error)
+	l.LoadAll([]int{5, 6, -7}, false, func(err error) { done <- err })
+	if err := <-done; err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if v, _ := l.Get(5); v != 5 {
+		t.Fatalf("key %v shouldn't be replaced", 5)
+	}
+
+	if v, _ := l.Get(6); v != 60 {
+		t.Fatalf("key %v should be loaded", 6)
+	}
+
+	l.LoadAll([]int{5}, true, func(err error) { done <- err })
+	if err := <-done; err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if v, _ := l.Get(5); v != 50 {
+		t.Fatalf("key %v should be replaced", 5)
+	}
+
+	l, err = newLRU[int, int]("sample", 10, nil, true)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.LoadAll([]int{1}, false, func(err error) { done <- err })
+	if err := <-done; err != goria.ErrNoLoader {
+		t.Fatalf("LoadAll should fail without a loader, got %v", err)
+	}
+}
+
+type testWriter struct {
+	fail  bool
+	store map[int]int
+}
+
+func (w *testWriter) Write(key, value int) error {
+	if w.fail {
+		return errors.New("write failed")
+	}
+	w.store[key] = value
+	return nil
+}
+
+func (w *testWriter) WriteAll(entries map[int]int) error {
+	if w.fail {
+		return errors.New("write all failed")
+	}
+	for k, v := range entries {
+		w.store[k] = v
+	}
+	return nil
+}
+
+func (w *testWriter) Delete(key int) error {
+	if w.fail {
+		return errors.New("delete failed")
+	}
+	delete(w.store, key)
+	return nil
+}
+
+func (w *testWriter) DeleteAll(keys []int) error {
+	if w.fail {
+		return errors.New("delete all failed")
+	}
+	for _, k := range keys {
+		delete(w.store, k)
+	}
+	return nil
+}
+
+func TestGoriaWriter(t *testing.T) {
+
+	writer := &testWriter{store: make(map[int]int)}
+	l, err := newLRUWithConfiguration("sample", goria.Configuration[int, int]{Size: 10, StatsEnabled: true, CacheWriter: writer, WriteThrough: true})
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Put(1, 1)
+	l.PutAll(map[int]int{2: 2, 3: 3, 4: 4})
+	l.PutIfAbsent(5, 5)
+	l.Replace(1, 1, 10)
+	l.ReplaceWithKeyOnly(2, 20)
+	l.GetAndReplace(3, 30)
+
+	for k, v := range map[int]int{1: 10, 2: 20, 3: 30, 4: 4, 5: 5} {
+		if writer.store[k] != v {
+			t.Fatalf("key %v should be written with a value of %v instead has a value of %v", k, v, writer.store[k])
+		}
+	}
+
+	l.RemoveWithKeyOnly(1)
+	l.Remove(2, 20)
+	l.GetAndRemove(3)
+	l.RemoveAll(map[int]int{4: 4})
+
+	if len(writer.store) != 1 || writer.store[5] != 5 {
+		t.Fatalf("Wrong written entries %v", writer.store)
+	}
+
+	writer.fail = true
+
+	if err := l.Put(6, 6); err == nil || l.ContainsKey(6) {
+		t.Fatalf("key %v shouldn't be put when the writer fails", 6)
+	}
+
+	if err := l.PutAll(map[int]int{7: 7}); err == nil || l.ContainsKey(7) {
+		t.Fatalf("key %v shouldn't be put when the writer fails", 7)
+	}
+
+	if ok, err := l.Replace(5, 5, 50); ok || err == nil {
+		t.Fatalf("key %v shouldn't be replaced when the writer fails", 5)
+	}
+
+	if ok, err := l.RemoveWithKeyOnly(5); ok || err == nil || !l.ContainsKey(5) {
+		t.Fatalf("key %v shouldn't be removed when the writer fails", 5)
+	}
+
+	if err := l.RemoveAllWithoutParameters(); err == nil || l.Len() != 1 {
+		t.Fatalf("cache shouldn't be emptied when the writer fails")
+	}
+
+	if v, _ := l.Get(5); v != 5 {
+		t.Fatalf("key %v should have a value of %v", 5, 5)
+	}
+
+	writer.fail = false
+
+	if err := l.RemoveAllWithoutParameters(); err != nil || l.Len() != 0 || len(writer.store) != 0 {
+		t.Fatalf("cache and writer should be emptied")
+	}
+}
+
+func TestGoriaInvoke(t *testing.T) {
+
+	l, err := newLRUWithConfiguration("sample", goria.Configuration[int, int]{Size: 10, StatsEnabled: true, CacheLoader: &testLoader{}, ReadThrough: true})
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	increment := func(e goria.MutableEntry[int, int], args ...interface{}) (interface{}, error) {
+		v := 0
+		if e.Exists() {
+			v = e.GetValue()
+		}
+		e.SetValue(v + args[0].(int))
+		return e.GetValue(), nil
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				l.Invoke(-1, increment, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if v, _ := l.Get(-1); v != 800 {
+		t.Fatalf("counter should be %v instead is %v", 800, v)
+	}
+
+	result, err := l.Invoke(2, func(e goria.MutableEntry[int, int], args ...interface{}) (interface{}, error) {
+		return e.GetValue(), nil
+	})
+
+	if err != nil || result != 20 || !l.ContainsKey(2) {
+		t.Fatalf("key %v should be loaded by the processor", 2)
+	}
+
+	result, err = l.Invoke(2, func(e goria.MutableEntry[int, int], args ...interface{}) (interface{}, error) {
+		e.Remove()
+		return e.Exists(), nil
+	})
+
+	if err != nil || result != false || l.ContainsKey(2) {
+		t.Fatalf("key %v should be removed by the processor", 2)
+	}
+
+	_, err = l.Invoke(-1, func(e goria.MutableEntry[int, int], args ...interface{}) (interface{}, error) {
+		e.SetValue(0)
+		return nil, errors.New("processor failed")
+	})
+
+	if v, _ := l.Get(-1); err == nil || v != 800 {
+		t.Fatalf("key %v shouldn't be updated by a failing processor", -1)
+	}
+
+	results := l.InvokeAll([]int{-1, -2, -3}, increment, 10)
+
+	if len(results) != 3 || results[-1].Value != 810 || results[-2].Value != 10 || results[-3].Err != nil {
+		t.Fatalf("Wrong processor results %v", results)
+	}
+
+	if l.Len() != 3 {
+		t.Fatalf("Wrong len %v", l.Len())
+	}
+}
+
+func TestGoriaListeners(t *testing.T) {
+
+	var evicted []int
+	l, err := newLRUWithConfiguration("sample", goria.Configuration[int, int]{
+		Size:             2,
+		EvictionCallback: func(key int, value int) { evicted = append(evicted, key) },
+		ExpiryPolicy:     goria.NewCreatedExpiryPolicy(time.Minute),
+	})
+
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	var events, removals []goria.CacheEntryEvent[int, int]
+	id := l.RegisterCacheEntryListener(func(event goria.CacheEntryEvent[int, int]) { events = append(events, event) }, nil)
+	l.RegisterCacheEntryListener(func(event goria.CacheEntryEvent[int, int]) { removals = append(removals, event) }, goria.EventTypeFilter[int, int](goria.Removed))
+
+	l.Put(1, 1)
+	l.Put(1, 10)
+	l.Replace(1, 10, 100)
+	l.RemoveWithKeyOnly(1)
+	l.Put(2, 2)
+	now = now.Add(2 * time.Minute)
+	l.Get(2)
+	l.Put(3, 3)
+	l.Put(4, 4)
+	l.Put(5, 5)
+
+	expected := []goria.CacheEntryEvent[int, int]{
+		{Type: goria.Created, Key: 1, Value: 1},
+		{Type: goria.Updated, Key: 1, Value: 10, OldValue: 1},
+		{Type: goria.Updated, Key: 1, Value: 100, OldValue: 10},
+		{Type: goria.Removed, Key: 1, Value: 100, OldValue: 100},
+		{Type: goria.Created, Key: 2, Value: 2},
+		{Type: goria.Expired, Key: 2, Value: 2, OldValue: 2},
+		{Type: goria.Created, Key: 3, Value: 3},
+		{Type: goria.Created, Key: 4, Value: 4},
+		{Type: goria.Created, Key: 5, Value: 5},
+		{Type: goria.Evicted, Key: 3, Value: 3, OldValue: 3},
+	}
+
+	if len(events) != len(expected) {
+		t.Fatalf("Wrong events %v", events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Fatalf("event %v should be %v instead is %v", i, expected[i], events[i])
+		}
+	}
+
+	if len(removals) != 1 || removals[0].Key != 1 {
+		t.Fatalf("Wrong filtered events %v", removals)
+	}
+
+	if len(evicted) != 1 || evicted[0] != 3 {
+		t.Fatalf("eviction callback should be invoked only for evictions, got %v", evicted)
+	}
+
+	if !l.DeregisterCacheEntryListener(id) || l.DeregisterCacheEntryListener(id) {
+		t.Fatalf("listener %v should be deregistered once", id)
+	}
+
+	l.Put(6, 6)
+
+	if len(events) != len(expected) {
+		t.Fatalf("deregistered listener shouldn't receive events %v", events[len(expected):])
+	}
+}
+
+func TestGoriaWeight(t *testing.T) {
+
+	var evicted []int
+	writer := &testWriter{store: make(map[int]int)}
+	l, err := newLRUWithConfiguration("sample", goria.Configuration[int, int]{
+		Size:             10,
+		MaxWeight:        10,
+		Weigher:          func(key int, value int) int64 { return int64(value) },
+		EvictionCallback: func(key int, value int) { evicted = append(evicted, key) },
+		StatsEnabled:     true,
+		CacheWriter:      writer,
+		WriteThrough:     true,
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	if l.GetSize() != 10 || l.GetMaxWeight() != 10 {
+		t.Fatalf("Wrong size %v or max weight %v", l.GetSize(), l.GetMaxWeight())
+	}
+
+	l.Put(1, 3)
+	l.Put(2, 3)
+	l.Put(3, 3)
+	l.Put(4, 4)
+
+	if l.Len() != 3 || len(evicted) != 1 || evicted[0] != 1 {
+		t.Fatalf("Wrong len %v or evicted keys %v", l.Len(), evicted)
+	}
+
+	if l.GetStats().Weight != 10 || l.GetStats().Evictions != 1 {
+		t.Fatalf("Wrong Weight stat %v or Evictions stat %v", l.GetStats().Weight, l.GetStats().Evictions)
+	}
+
+	if err := l.Put(5, 11); !errors.Is(err, goria.ErrEntryTooHeavy) {
+		t.Fatalf("an entry heavier than the maximum weight should be rejected, got %v", err)
+	}
+
+	if _, ok := writer.store[5]; ok || l.ContainsKey(5) || l.Len() != 3 {
+		t.Fatalf("a rejected entry should reach neither the writer nor the cache")
+	}
+
+	if err := l.PutAll(map[int]int{6: 1, 7: 20}); !errors.Is(err, goria.ErrEntryTooHeavy) {
+		t.Fatalf("PutAll should reject an entry heavier than the maximum weight, got %v", err)
+	}
+
+	if l.ContainsKey(6) || l.ContainsKey(7) {
+		t.Fatalf("PutAll should leave the cache untouched when an entry is rejected")
+	}
+
+	// growing an entry evicts the others until the weight fits
+	if result, _ := l.ReplaceWithKeyOnly(2, 8); !result {
+		t.Fatalf("key %v should be replaced", 2)
+	}
+
+	if l.Len() != 1 || l.GetStats().Weight > 10 || !l.ContainsKey(2) {
+		t.Fatalf("Wrong len %v or Weight stat %v", l.Len(), l.GetStats().Weight)
+	}
+
+	l.Clear()
+
+	if l.GetStats().Weight != 0 {
+		t.Fatalf("Wrong Weight stat %v", l.GetStats().Weight)
+	}
+}
+
+func TestGoriaStatistics(t *testing.T) {
+
+	now := time.Now()
+	// every reading of the clock moves it forward by a microsecond, so that every operation takes some time
+	clock := func() time.Time {
+		now = now.Add(time.Microsecond)
+		return now
+	}
+
+	l, err := newLRUWithConfiguration("sample", goria.Configuration[int, int]{Size: 3, StatsEnabled: true})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	SetClock(l, clock)
+
+	l.Put(1, 1)
+	l.PutAll(map[int]int{2: 2, 3: 3})
+	l.PutIfAbsent(1, 10)
+	l.Replace(1, 1, 11)
+	l.Replace(4, 4, 14)
+	l.ReplaceWithKeyOnly(2, 12)
+	l.GetAndReplace(3, 13)
+	l.Put(4, 4)
+
+	stats := l.GetStats()
+	if stats.Puts != 7 || stats.Evictions != 1 || stats.Removals != 0 {
+		t.Fatalf("Wrong Puts stat %v, Evictions stat %v or Removals stat %v", stats.Puts, stats.Evictions, stats.Removals)
+	}
+
+	if stats.Gets != 4 || stats.Hits != 3 || stats.Miss != 1 {
+		t.Fatalf("Wrong Gets stat %v, Hits stat %v or Miss stat %v", stats.Gets, stats.Hits, stats.Miss)
+	}
+
+	l.Get(4)
+	l.GetAll([]int{1, 2, 5})
+	l.Remove(2, 0)
+	l.Remove(2, 12)
+	l.RemoveWithKeyOnly(5)
+	l.GetAndRemove(3)
+	l.PutWithTTL(6, 6, time.Microsecond)
+	l.Get(6)
+
+	stats = l.GetStats()
+	if stats.Removals != 2 || stats.Expirations != 1 || stats.Evictions != 1 || stats.Items != 1 {
+		t.Fatalf("Wrong Removals stat %v, Expirations stat %v, Evictions stat %v or Items stat %v", stats.Removals, stats.Expirations, stats.Evictions, stats.Items)
+	}
+
+	if stats.Gets != 12 || stats.Hits != 8 || stats.Miss != 4 || stats.HitPercentage() != 100*8.0/12 {
+		t.Fatalf("Wrong Gets stat %v, Hits stat %v or Miss stat %v", stats.Gets, stats.Hits, stats.Miss)
+	}
+
+	if stats.GetTime < 5*time.Microsecond || stats.PutTime < 7*time.Microsecond || stats.RemoveTime < 2*time.Microsecond {
+		t.Fatalf("Wrong GetTime stat %v, PutTime stat %v or RemoveTime stat %v", stats.GetTime, stats.PutTime, stats.RemoveTime)
+	}
+
+	// every put and removal counted is observed once, the operations counting none such as the removal of an absent key are not,
+	// while the reads of Replace and Remove are measured with the put or the removal
+	if stats.PutLatency.Count() != stats.Puts || stats.RemoveLatency.Count() != stats.Removals || stats.GetLatency.Count() != 7 {
+		t.Fatalf("Wrong PutLatency count %v, RemoveLatency count %v or GetLatency count %v", stats.PutLatency.Count(), stats.RemoveLatency.Count(), stats.GetLatency.Count())
+	}
+
+	if stats.AverageRemoveTime() != float64(stats.RemoveTime/time.Microsecond)/2 || stats.AveragePutTime() < 1 {
+		t.Fatalf("Wrong average remove time %v or put time %v", stats.AverageRemoveTime(), stats.AveragePutTime())
+	}
+}
+
+func TestGoriaStatisticsControl(t *testing.T) {
+
+	l, err := newLRU[int, int]("sample", 10, nil, false)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	l.Put(1, 1)
+	l.Get(1)
+
+	if l.IsStatsEnabled() || l.GetStats() != (goria.CacheStats{Items: 1, Weight: 1, Capacity: 10}) {
+		t.Fatalf("statistics shouldn't be collected but Items, Weight and Capacity reported, got %+v", l.GetStats())
+	}
+
+	l.SetStatisticsEnabled(true)
+	l.Put(2, 2)
+	l.Get(1)
+	l.Get(3)
+
+	stats := l.GetStats()
+	if !l.IsStatsEnabled() || stats.Items != 2 || stats.Puts != 1 || stats.Gets != 2 || stats.Hits != 1 {
+		t.Fatalf("Wrong stats %+v", stats)
+	}
+
+	l.SetStatisticsEnabled(false)
+	l.Get(1)
+
+	if l.GetStats().Gets != 2 || l.GetStats().Items != 2 {
+		t.Fatalf("the counters should be kept while statistics are disabled, got %+v", l.GetStats())
+	}
+
+	l.SetStatisticsEnabled(true)
+	l.ClearStatistics()
+
+	if stats := l.GetStats(); stats.Gets != 0 || stats.Puts != 0 || stats.Items != 2 {
+		t.Fatalf("the counters should be reset but Items kept, got %+v", stats)
+	}
+
+	var wg sync.WaitGroup
+	var done atomic.Bool
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; !done.Load(); i++ {
+				l.Get(i % 20)
+				l.Put(g*100+i%20, i)
+			}
+		}(g)
+	}
+
+	for i := 0; i < 100; i++ {
+		stats := l.GetStats()
+		if stats.Gets != stats.Hits+stats.Miss || stats.Items > 10 {
+			done.Store(true)
+			t.Fatalf("the snapshot should be consistent, got %+v", stats)
+		}
+		if i%10 == 0 {
+			l.ClearStatistics()
+		}
+	}
+	done.Store(true)
+	wg.Wait()
+}
+
+// enablingLoader enables the statistics of cache while loading, as another goroutine could.
+type enablingLoader struct {
+	testLoader
+	cache *Cache[int, int]
+}
+
+func (l *enablingLoader) Load(key int) (int, bool, error) {
+	l.cache.SetStatisticsEnabled(true)
+	return l.testLoader.Load(key)
+}
+
+func TestGoriaStatisticsEnabledDuringLoad(t *testing.T) {
+
+	loader := &enablingLoader{}
+	l, err := newLRUWithConfiguration("sample", goria.Configuration[int, int]{Size: 10, CacheLoader: loader, ReadThrough: true})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	loader.cache = l
+
+	if v, ok := l.Get(1); !ok || v != 10 {
+		t.Fatalf("key 1 should be loaded with a value of 10, got %v", v)
+	}
+
+	// the read started while statistics were disabled, so it has no start time to be measured from
+	if stats := l.GetStats(); stats.Loads != 1 || stats.GetTime != 0 || stats.GetLatency.Count() != 0 {
+		t.Fatalf("Wrong Loads stat %v, GetTime stat %v or GetLatency count %v", stats.Loads, stats.GetTime, stats.GetLatency.Count())
+	}
+}
+
+// brokenPolicy returns victim, whatever the keys of the cache.
+type brokenPolicy struct {
+	*lruPolicy[int]
+	victim int
+	ok     bool
+}
+
+func (p *brokenPolicy) Victim(evictable func(key int) bool) (int, bool) {
+	return p.victim, p.ok
+}
+
+func TestGoriaNoVictim(t *testing.T) {
+
+	// 42 is not in the cache and 3 is the key being inserted
+	for _, p := range []*brokenPolicy{{victim: 0, ok: false}, {victim: 42, ok: true}, {victim: 3, ok: true}} {
+		p.lruPolicy = &lruPolicy[int]{list.New(), make(map[int]*list.Element)}
+		c, err := New("sample", goria.Configuration[int, int]{Size: 2}, goria.EvictionPolicy[int](p))
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+
+		c.Put(1, 1)
+		c.Put(2, 2)
+
+		if err := c.Put(3, 3); !errors.Is(err, goria.ErrNoVictim) {
+			t.Fatalf("a policy without a victim in the cache should fail the put, got %v for victim %v", err, p.victim)
+		}
+
+		if c.Len() != 3 {
+			t.Fatalf("the cache should be left over capacity, got len %v", c.Len())
+		}
+	}
+}
+
+// testRecorder counts the operations recorded by a cache, by cache name.
+type testRecorder struct {
+	hits       map[string]int
+	misses     map[string]int
+	evictions  map[goria.EventType]int
+	loads      int
+	loadErrors int
+}
+
+func newTestRecorder() *testRecorder {
+	return &testRecorder{hits: make(map[string]int), misses: make(map[string]int), evictions: make(map[goria.EventType]int)}
+}
+
+func (r *testRecorder) RecordHit(cache string) {
+	r.hits[cache]++
+}
+
+func (r *testRecorder) RecordMiss(cache string) {
+	r.misses[cache]++
+}
+
+func (r *testRecorder) RecordEviction(cache string, reason goria.EventType) {
+	r.evictions[reason]++
+}
+
+func (r *testRecorder) RecordLoad(cache string, d time.Duration, err error) {
+	r.loads++
+	if err != nil {
+		r.loadErrors++
+	}
+}
+
+func TestGoriaMetricsRecorder(t *testing.T) {
+
+	now := time.Now()
+	recorder := newTestRecorder()
+	loader := &testLoader{}
+	l, err := newLRUWithConfiguration("sample", goria.Configuration[int, int]{Size: 2, CacheLoader: loader, ReadThrough: true, MetricsRecorder: recorder})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	l.now = func() time.Time { return now }
+
+	l.Put(1, 1)
+	l.Put(2, 2)
+	l.Get(1)
+	l.Get(-1)
+	l.Put(3, 3)
+	l.RemoveWithKeyOnly(1)
+	l.PutWithTTL(4, 4, time.Second)
+
+	now = now.Add(2 * time.Second)
+
+	if v, ok := l.Get(4); !ok || v != 40 {
+		t.Fatalf("key %v should be expired then loaded, got %v", 4, v)
+	}
+
+	loader.fail = true
+	if _, err := l.GetAllOrLoad([]int{5, 6}); err == nil {
+		t.Fatalf("keys should fail to load")
+	}
+
+	if recorder.hits["sample"] != 1 || recorder.misses["sample"] != 4 {
+		t.Fatalf("Wrong recorded hits %v or misses %v", recorder.hits, recorder.misses)
+	}
+
+	if len(recorder.evictions) != 2 || recorder.evictions[goria.Evicted] != 1 || recorder.evictions[goria.Expired] != 1 {
+		t.Fatalf("Wrong recorded evictions %v", recorder.evictions)
+	}
+
+	if recorder.loads != 3 || recorder.loadErrors != 1 {
+		t.Fatalf("Wrong recorded loads %v or load errors %v", recorder.loads, recorder.loadErrors)
+	}
+
+	if l.GetStats().Gets != 0 {
+		t.Fatalf("the recorder shouldn't depend on the statistics, got %v", l.GetStats())
+	}
+}
@@ -0,0 +1,81 @@
+package engine
+
+import "github.com/oscerd/goria"
+
+type entryOperation int
+
+const (
+	operationNone entryOperation = iota
+	operationLoad
+	operationSet
+	operationRemove
+)
+
+// mutableEntry records what an EntryProcessor does to an entry, the cache is mutated by apply.
+type mutableEntry[K comparable, V any] struct {
+	cache     *Cache[K, V]
+	key       K
+	value     V
+	exists    bool
+	accessed  bool
+	operation entryOperation
+}
+
+var _ goria.MutableEntry[string, interface{}] = (*mutableEntry[string, interface{}])(nil)
+
+func (e *mutableEntry[K, V]) GetKey() K {
+	return e.key
+}
+
+func (e *mutableEntry[K, V]) Exists() bool {
+	return e.exists
+}
+
+func (e *mutableEntry[K, V]) GetValue() V {
+	if e.accessed || e.operation != operationNone {
+		return e.value
+	}
+	e.accessed = true
+
+	e.value, e.exists = e.cache.get(e.key)
+	if !e.exists && e.cache.readThrough {
+		value, exists, err := e.cache.load(e.key)
+		if err != nil {
+			if e.cache.IsStatsEnabled() {
+				e.cache.stats.LoadErrors++
+			}
+		} else if exists {
+			if e.cache.IsStatsEnabled() {
+				e.cache.stats.Loads++
+			}
+			e.value, e.exists, e.operation = value, true, operationLoad
+		}
+	}
+	return e.value
+}
+
+func (e *mutableEntry[K, V]) SetValue(value V) {
+	e.value, e.exists, e.operation = value, true, operationSet
+}
+
+func (e *mutableEntry[K, V]) Remove() {
+	var zero V
+	e.value, e.exists, e.operation = zero, false, operationRemove
+}
+
+func (e *mutableEntry[K, V]) apply() error {
+	switch e.operation {
+	case operationLoad:
+		return e.cache.put(e.key, e.value)
+	case operationSet:
+		if err := e.cache.write(e.key, e.value); err != nil {
+			return err
+		}
+		return e.cache.put(e.key, e.value)
+	case operationRemove:
+		if _, err := e.cache.remove(e.key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+/*
+Package keyhash hashes the keys of a cache whatever their comparable type, the way
+maphash.Comparable does from Go 1.24 on, so that Goria builds with older toolchains.
+*/
+package keyhash
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+// Sum returns the hash of key with seed, equal keys having equal sums. The strings and the integers
+// are hashed directly, the other keys are walked through reflection.
+func Sum[K comparable](seed maphash.Seed, key K) uint64 {
+	switch k := any(key).(type) {
+	case string:
+		return maphash.String(seed, k)
+	case int:
+		return integer(seed, uint64(k))
+	case int64:
+		return integer(seed, uint64(k))
+	case int32:
+		return integer(seed, uint64(k))
+	case uint:
+		return integer(seed, uint64(k))
+	case uint64:
+		return integer(seed, k)
+	case uint32:
+		return integer(seed, uint64(k))
+	}
+	var h maphash.Hash
+	h.SetSeed(seed)
+	write(&h, reflect.ValueOf(&key).Elem())
+	return h.Sum64()
+}
+
+func integer(seed maphash.Seed, v uint64) uint64 {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return maphash.Bytes(seed, b[:])
+}
+
+// write writes v to h, so that values equal under == write the same bytes.
+func write(h *maphash.Hash, v reflect.Value) {
+	var b [8]byte
+	switch v.Kind() {
+	case reflect.String:
+		h.WriteString(v.String())
+		return
+	case reflect.Bool:
+		if v.Bool() {
+			b[0] = 1
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		binary.LittleEndian.PutUint64(b[:], uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		binary.LittleEndian.PutUint64(b[:], v.Uint())
+	case reflect.Float32, reflect.Float64:
+		binary.LittleEndian.PutUint64(b[:], float(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		binary.LittleEndian.PutUint64(b[:], float(real(v.Complex())))
+		h.Write(b[:])
+		binary.LittleEndian.PutUint64(b[:], float(imag(v.Complex())))
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		binary.LittleEndian.PutUint64(b[:], uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			h.WriteByte(0)
+			return
+		}
+		write(h, v.Elem())
+		return
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			write(h, v.Index(i))
+		}
+		return
+	case reflect.Struct:
+		// the blank fields are left out of the comparison of structs
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Name != "_" {
+				write(h, v.Field(i))
+			}
+		}
+		return
+	}
+	h.Write(b[:])
+}
+
+// float returns the bits of f, +0 and -0 being equal.
+func float(f float64) uint64 {
+	if f == 0 {
+		return 0
+	}
+	return math.Float64bits(f)
+}
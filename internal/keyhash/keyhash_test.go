@@ -0,0 +1,44 @@
+package keyhash
+
+import (
+	"hash/maphash"
+	"math"
+	"testing"
+)
+
+type point struct {
+	x, y float64
+	name string
+	_    int
+	next *point
+}
+
+func TestSum(t *testing.T) {
+
+	seed := maphash.MakeSeed()
+	p := &point{}
+
+	equal := [][2]any{
+		{"a", "a"},
+		{42, 42},
+		{uint8(7), uint8(7)},
+		{0.0, math.Copysign(0, -1)},
+		{point{1, 2, "a", 3, p}, point{1, 2, "a", 4, p}},
+		{[2]point{{x: math.Copysign(0, -1)}}, [2]point{{x: 0}}},
+		{any(nil), any(nil)},
+		{complex(1, 0), complex(1, math.Copysign(0, -1))},
+	}
+	for _, pair := range equal {
+		if Sum(seed, pair[0]) != Sum(seed, pair[1]) {
+			t.Fatalf("equal keys %v and %v should have equal sums", pair[0], pair[1])
+		}
+	}
+
+	if Sum(seed, point{x: 1}) == Sum(seed, point{x: 2}) || Sum(seed, p) == Sum(seed, &point{}) || Sum(seed, "a") == Sum(seed, "b") {
+		t.Fatalf("other keys should have other sums")
+	}
+
+	if Sum(seed, 42) != Sum(seed, any(42)) || Sum(seed, "a") != Sum(seed, any("a")) {
+		t.Fatalf("the sum of a key should not depend on its static type")
+	}
+}
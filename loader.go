@@ -0,0 +1,15 @@
+package goria
+
+import "errors"
+
+var ErrNoLoader = errors.New("The Goria Cache has no CacheLoader configured")
+
+// CacheLoader fetches the values missing from a cache out of an external resource, in the spirit of the JSR 107 CacheLoader.
+// Load reports with exists whether a value was found for key, LoadAll leaves the keys without a value out of the returned map.
+type CacheLoader[K comparable, V any] interface {
+	Load(key K) (value V, exists bool, err error)
+	LoadAll(keys []K) (map[K]V, error)
+}
+
+// CompletionListener is notified once a LoadAll has completed, err is nil when it succeeded.
+type CompletionListener func(err error)
@@ -0,0 +1,135 @@
+package goria
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	ErrCacheExists   = errors.New("The Goria Cache already exists")
+	ErrManagerClosed = errors.New("The Goria CacheManager is closed")
+	ErrNoFactory     = errors.New("The Goria Cache configuration need a Factory")
+	ErrCacheNotFound = errors.New("The Goria Cache does not exist")
+	ErrCacheType     = errors.New("The Goria Cache has other key or value types")
+)
+
+// managedCache is the part of a Cache the manager needs, whatever its key and value types.
+type managedCache interface {
+	GetName() string
+	GetStats() CacheStats
+	Clear()
+}
+
+// CacheManager is a registry of named caches, in the spirit of the JSR 107 CacheManager.
+// Caches of any key and value types are created and looked up through the CreateCache and GetCache functions.
+type CacheManager struct {
+	mu     sync.Mutex
+	caches map[string]managedCache
+	closed bool
+}
+
+func NewCacheManager() *CacheManager {
+	return &CacheManager{
+		caches: make(map[string]managedCache),
+	}
+}
+
+// CreateCache builds a cache through config.Factory and registers it under name in m.
+func CreateCache[K comparable, V any](m *CacheManager, name string, config Configuration[K, V]) (Cache[K, V], error) {
+	if config.Factory == nil {
+		return nil, ErrNoFactory
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, ErrManagerClosed
+	}
+	if _, exists := m.caches[name]; exists {
+		return nil, fmt.Errorf("%w: %v", ErrCacheExists, name)
+	}
+
+	c, err := config.Factory(name, config)
+	if err != nil {
+		return nil, err
+	}
+	m.caches[name] = c
+	return c, nil
+}
+
+// GetCache returns the cache registered under name in m, which must have been created with the same key and value types.
+func GetCache[K comparable, V any](m *CacheManager, name string) (Cache[K, V], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, exists := m.caches[name]
+	if !exists {
+		return nil, fmt.Errorf("%w: %v", ErrCacheNotFound, name)
+	}
+	typed, ok := c.(Cache[K, V])
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", ErrCacheType, name)
+	}
+	return typed, nil
+}
+
+// DestroyCache clears the cache registered under name and forgets it.
+func (m *CacheManager) DestroyCache(name string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, exists := m.caches[name]
+	if !exists {
+		return false
+	}
+	delete(m.caches, name)
+	c.Clear()
+	return true
+}
+
+// CacheNames returns the names of the managed caches in lexical order.
+func (m *CacheManager) CacheNames() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, 0, len(m.caches))
+	for name := range m.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Stats returns the statistics of every managed cache by name.
+func (m *CacheManager) Stats() map[string]CacheStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make(map[string]CacheStats, len(m.caches))
+	for name, c := range m.caches {
+		stats[name] = c.GetStats()
+	}
+	return stats
+}
+
+// Close clears every managed cache, after that the manager refuses to create new caches.
+func (m *CacheManager) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for name, c := range m.caches {
+		c.Clear()
+		delete(m.caches, name)
+	}
+	m.closed = true
+}
+
+func (m *CacheManager) IsClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.closed
+}
@@ -0,0 +1,114 @@
+package goria_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/oscerd/goria"
+	"github.com/oscerd/goria/gorialru"
+	"github.com/oscerd/goria/goriamru"
+)
+
+func TestCacheManager(t *testing.T) {
+
+	m := goria.NewCacheManager()
+
+	lru, err := goria.CreateCache(m, "lru", goria.Configuration[int, int]{Size: 5, StatsEnabled: true, Factory: gorialru.Factory[int, int]})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	_, err = goria.CreateCache(m, "mru", goria.Configuration[int, int]{Size: 5, StatsEnabled: true, Factory: goriamru.Factory[int, int]})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	_, err = goria.CreateCache(m, "lru", goria.Configuration[int, int]{Size: 5, Factory: goriamru.Factory[int, int]})
+	if !errors.Is(err, goria.ErrCacheExists) {
+		t.Fatalf("cache %v should be rejected as duplicated, got %v", "lru", err)
+	}
+
+	_, err = goria.CreateCache(m, "other", goria.Configuration[int, int]{Size: 5})
+	if err != goria.ErrNoFactory {
+		t.Fatalf("cache %v should be rejected without a factory, got %v", "other", err)
+	}
+
+	_, err = goria.CreateCache(m, "other", goria.Configuration[int, int]{Size: 0, Factory: gorialru.Factory[int, int]})
+	if err == nil {
+		t.Fatalf("cache %v should be rejected with a zero size", "other")
+	}
+
+	names := m.CacheNames()
+	if len(names) != 2 || names[0] != "lru" || names[1] != "mru" {
+		t.Fatalf("Wrong cache names %v", names)
+	}
+
+	for i := 0; i < 10; i++ {
+		lru.Put(i, i)
+	}
+
+	c, err := goria.GetCache[int, int](m, "lru")
+	if err != nil || c != lru {
+		t.Fatalf("cache %v should be registered", "lru")
+	}
+
+	if c.GetName() != "lru" || c.Len() != 5 {
+		t.Fatalf("Wrong cache %v with len %v", c.GetName(), c.Len())
+	}
+
+	if _, err = goria.GetCache[string, int](m, "mru"); !errors.Is(err, goria.ErrCacheType) {
+		t.Fatalf("cache %v shouldn't be returned with other key types, got %v", "mru", err)
+	}
+
+	if !m.DestroyCache("lru") {
+		t.Fatalf("cache %v should be destroyed", "lru")
+	}
+
+	if lru.Len() != 0 {
+		t.Fatalf("destroyed cache should be empty")
+	}
+
+	if _, err = goria.GetCache[int, int](m, "lru"); !errors.Is(err, goria.ErrCacheNotFound) {
+		t.Fatalf("cache %v shouldn't be registered", "lru")
+	}
+
+	if m.DestroyCache("lru") {
+		t.Fatalf("cache %v shouldn't be destroyed twice", "lru")
+	}
+
+	m.Close()
+
+	if !m.IsClosed() {
+		t.Fatalf("manager should be closed")
+	}
+
+	if len(m.CacheNames()) != 0 {
+		t.Fatalf("closed manager shouldn't have caches %v", m.CacheNames())
+	}
+
+	_, err = goria.CreateCache(m, "lru", goria.Configuration[int, int]{Size: 5, Factory: gorialru.Factory[int, int]})
+	if err != goria.ErrManagerClosed {
+		t.Fatalf("closed manager shouldn't create caches, got %v", err)
+	}
+}
+
+func TestCacheManagerStats(t *testing.T) {
+
+	m := goria.NewCacheManager()
+
+	lru, err := goria.CreateCache(m, "lru", goria.Configuration[int, int]{Size: 5, StatsEnabled: true, Factory: gorialru.Factory[int, int]})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, err := goria.CreateCache(m, "mru", goria.Configuration[string, int]{Size: 3, StatsEnabled: true, Factory: goriamru.Factory[string, int]}); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	lru.Put(1, 1)
+	lru.Get(1)
+
+	stats := m.Stats()
+	if len(stats) != 2 || stats["lru"].Hits != 1 || stats["lru"].Capacity != 5 || stats["mru"].Capacity != 3 {
+		t.Fatalf("Wrong stats %+v", stats)
+	}
+}
@@ -0,0 +1,21 @@
+package goria
+
+// MutableEntry is the view of a cache entry handed to an EntryProcessor, in the spirit of the JSR 107 MutableEntry.
+// GetValue reads the entry through the CacheLoader when the cache is read through, SetValue and Remove
+// are applied to the cache only once the processor has returned without error.
+type MutableEntry[K comparable, V any] interface {
+	GetKey() K
+	Exists() bool
+	GetValue() V
+	SetValue(value V)
+	Remove()
+}
+
+// EntryProcessor is invoked atomically on a single entry, the entry must not be used once the processor has returned.
+type EntryProcessor[K comparable, V any] func(entry MutableEntry[K, V], args ...interface{}) (interface{}, error)
+
+// EntryProcessorResult holds the outcome of an EntryProcessor run by InvokeAll.
+type EntryProcessorResult struct {
+	Value interface{}
+	Err   error
+}
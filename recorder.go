@@ -0,0 +1,31 @@
+package goria
+
+import "time"
+
+// MetricsRecorder is told about every operation of a cache as it happens, so that the cache can be bridged
+// to a telemetry library without Goria depending on it; cache is the name of the cache recording the operation.
+// RecordEviction is called with Evicted or Expired as reason, RecordLoad once per call to the CacheLoader,
+// Load or LoadAll, with the time it took and its error.
+// RecordHit, RecordMiss and RecordEviction are invoked while the cache lock is held, so a MetricsRecorder
+// must be quick and must not call back into the cache. RecordLoad is invoked without it and the segments of
+// a goriasharded cache share their MetricsRecorder, so a MetricsRecorder must be safe for concurrent use.
+type MetricsRecorder interface {
+	RecordHit(cache string)
+	RecordMiss(cache string)
+	RecordEviction(cache string, reason EventType)
+	RecordLoad(cache string, d time.Duration, err error)
+}
+
+// NoopMetricsRecorder records nothing, it can be embedded by the recorders interested in some operations only.
+// A cache without a MetricsRecorder skips the recording altogether.
+type NoopMetricsRecorder struct{}
+
+var _ MetricsRecorder = NoopMetricsRecorder{}
+
+func (NoopMetricsRecorder) RecordHit(cache string) {}
+
+func (NoopMetricsRecorder) RecordMiss(cache string) {}
+
+func (NoopMetricsRecorder) RecordEviction(cache string, reason EventType) {}
+
+func (NoopMetricsRecorder) RecordLoad(cache string, d time.Duration, err error) {}
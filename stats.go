@@ -0,0 +1,157 @@
+package goria
+
+import "time"
+
+// CacheStats holds the counters collected by a cache when statistics are enabled, after the
+// CacheStatisticsMXBean of JSR 107, along with Items, Weight and Capacity which are reported whether
+// statistics are enabled or not.
+// Gets counts the reads of the cache, Hits and Miss telling whether the key was found, Puts the values
+// written by the API methods, the loaded values being counted by Loads, Removals the entries removed
+// through the API methods, Evictions the entries evicted to make room for others and Expirations the
+// entries removed once expired.
+// AdmissionRejections counts the new entries evicted by an admission policy in favour of the entries already cached,
+// Weight is the sum of the weights of the entries and Capacity the number of entries the cache can hold.
+// GetTime, PutTime and RemoveTime are the time spent in the get, put and remove methods counting a read, a put
+// or a removal, whose durations are counted by GetLatency, PutLatency and RemoveLatency once per operation counted,
+// a method counting several operations, such as PutAll, sharing its time evenly among them.
+type CacheStats struct {
+	Items               int64
+	Gets                int64
+	Hits                int64
+	Evictions           int64
+	Miss                int64
+	Loads               int64
+	LoadErrors          int64
+	AdmissionRejections int64
+	Weight              int64
+	Capacity            int64
+	Puts                int64
+	Removals            int64
+	Expirations         int64
+	GetTime             time.Duration
+	PutTime             time.Duration
+	RemoveTime          time.Duration
+	GetLatency          LatencyHistogram
+	PutLatency          LatencyHistogram
+	RemoveLatency       LatencyHistogram
+}
+
+// LatencyBuckets are the upper bounds of the buckets of a LatencyHistogram.
+var LatencyBuckets = [...]time.Duration{
+	time.Microsecond,
+	5 * time.Microsecond,
+	10 * time.Microsecond,
+	50 * time.Microsecond,
+	100 * time.Microsecond,
+	500 * time.Microsecond,
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// LatencyHistogram counts the operations by duration, the i-th count being for the durations up to
+// LatencyBuckets[i] and above the previous bound, the last one for the durations above every bound.
+type LatencyHistogram [len(LatencyBuckets) + 1]int64
+
+// Observe counts an operation lasting d.
+func (h *LatencyHistogram) Observe(d time.Duration) {
+	i := 0
+	for i < len(LatencyBuckets) && d > LatencyBuckets[i] {
+		i++
+	}
+	h[i]++
+}
+
+// Count returns the number of operations counted.
+func (h LatencyHistogram) Count() int64 {
+	count := int64(0)
+	for _, n := range h {
+		count += n
+	}
+	return count
+}
+
+// Add returns the sum of h and other, bucket by bucket.
+func (h LatencyHistogram) Add(other LatencyHistogram) LatencyHistogram {
+	for i := range h {
+		h[i] += other[i]
+	}
+	return h
+}
+
+// Sub returns the difference between h and other, bucket by bucket.
+func (h LatencyHistogram) Sub(other LatencyHistogram) LatencyHistogram {
+	for i := range h {
+		h[i] -= other[i]
+	}
+	return h
+}
+
+// HitPercentage returns the percentage of the reads finding their key, 0 when the cache has not been read.
+func (s CacheStats) HitPercentage() float64 {
+	return percentage(s.Hits, s.Gets)
+}
+
+// MissPercentage returns the percentage of the reads not finding their key, 0 when the cache has not been read.
+func (s CacheStats) MissPercentage() float64 {
+	return percentage(s.Miss, s.Gets)
+}
+
+// AverageGetTime returns the mean time of a read in microseconds.
+func (s CacheStats) AverageGetTime() float64 {
+	return average(s.GetTime, s.Gets)
+}
+
+// AveragePutTime returns the mean time of a put in microseconds.
+func (s CacheStats) AveragePutTime() float64 {
+	return average(s.PutTime, s.Puts)
+}
+
+// AverageRemoveTime returns the mean time of a removal in microseconds.
+func (s CacheStats) AverageRemoveTime() float64 {
+	return average(s.RemoveTime, s.Removals)
+}
+
+// Sub returns the statistics collected since previous, an earlier snapshot of the same cache, so that the
+// counters of an interval can be computed. Items, Weight and Capacity are those of s.
+func (s CacheStats) Sub(previous CacheStats) CacheStats {
+	return CacheStats{
+		Items:               s.Items,
+		Gets:                s.Gets - previous.Gets,
+		Hits:                s.Hits - previous.Hits,
+		Evictions:           s.Evictions - previous.Evictions,
+		Miss:                s.Miss - previous.Miss,
+		Loads:               s.Loads - previous.Loads,
+		LoadErrors:          s.LoadErrors - previous.LoadErrors,
+		AdmissionRejections: s.AdmissionRejections - previous.AdmissionRejections,
+		Weight:              s.Weight,
+		Capacity:            s.Capacity,
+		Puts:                s.Puts - previous.Puts,
+		Removals:            s.Removals - previous.Removals,
+		Expirations:         s.Expirations - previous.Expirations,
+		GetTime:             s.GetTime - previous.GetTime,
+		PutTime:             s.PutTime - previous.PutTime,
+		RemoveTime:          s.RemoveTime - previous.RemoveTime,
+		GetLatency:          s.GetLatency.Sub(previous.GetLatency),
+		PutLatency:          s.PutLatency.Sub(previous.PutLatency),
+		RemoveLatency:       s.RemoveLatency.Sub(previous.RemoveLatency),
+	}
+}
+
+func percentage(count, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) * 100 / float64(total)
+}
+
+func average(d time.Duration, count int64) float64 {
+	if count == 0 {
+		return 0
+	}
+	return float64(d) / float64(time.Microsecond) / float64(count)
+}
@@ -0,0 +1,10 @@
+package goria
+
+import "errors"
+
+var ErrEntryTooHeavy = errors.New("The Goria Cache entry is heavier than the maximum weight")
+
+// Weigher returns the weight of an entry, such as the size in bytes of its value.
+// Weighers are invoked while the cache lock is held so they must not call back into the cache,
+// and the Weigher shared by the segments of a goriasharded cache must be safe for concurrent use.
+type Weigher[K comparable, V any] func(key K, value V) int64
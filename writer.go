@@ -0,0 +1,10 @@
+package goria
+
+// CacheWriter writes the mutations of a cache through to an external resource, in the spirit of the JSR 107 CacheWriter.
+// It is invoked synchronously before the cache is mutated, an error aborts the mutation and is returned to the caller.
+type CacheWriter[K comparable, V any] interface {
+	Write(key K, value V) error
+	WriteAll(entries map[K]V) error
+	Delete(key K) error
+	DeleteAll(keys []K) error
+}